@@ -4,30 +4,72 @@ package gofastersql
 
 import (
 	"database/sql"
+	"encoding"
+	"errors"
+	"fmt"
 	nt "github.com/dakusan/gofastersql/nulltypes"
+	"io"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 )
 
 type upt unsafe.Pointer
 
+// TrimNumericSpaces removes surrounding ASCII whitespace from columns before they are parsed into integers and floats (ex: “ 42 ” from a padded CHAR column). Defaults to false (strict parsing).
+// Every numeric conversion reads it, so it cannot be flipped while other goroutines are scanning.
+var TrimNumericSpaces = false
+
+// EmptyStringIsNull makes nullable members (nulltypes, sql.Null* and sql.Null[T]) treat empty columns as NULL, for schemas that store missing values as empty strings. Defaults to false.
+// Non-nullable members still receive the empty column (ex: "" for strings). It is read on each nullable conversion, so set it before any readers are in use.
+var EmptyStringIsNull = false
+
+// ZeroDateMode determines how MySQL’s zero date (“0000-00-00 00:00:00”) is converted into times
+type ZeroDateMode uint8
+
+const (
+	ZeroDateToZeroTime ZeroDateMode = iota //Converts to time.Time{} (default)
+	ZeroDateToUnixZero                     //Converts to time.Unix(0, 0).UTC() (the same as NULL by default)
+	ZeroDateError                          //Returns an error
+)
+
+// ZeroDateHandling determines how MySQL’s zero date (“0000-00-00 00:00:00”, or just “0000-00-00”) is converted into times. Defaults to ZeroDateToZeroTime.
+// Use ZeroDateError to catch legacy zero dates, or ZeroDateToUnixZero to treat them like NULL. It is checked each time a zero date is converted, so choose the mode at startup.
+var ZeroDateHandling = ZeroDateToZeroTime
+
+// NullTimeMode determines how NULL is converted into times
+type NullTimeMode uint8
+
+const (
+	NullTimeToUnixZero NullTimeMode = iota //Converts to time.Unix(0, 0) in the reader’s TimeLocation (default)
+	NullTimeToZeroTime                     //Converts to time.Time{}
+	NullTimeError                          //Returns an error. Nullable members (ex: nulltypes.NullTime) are still set to NULL, with their value set to time.Time{}
+)
+
+// NullTimeHandling determines how NULL is converted into time.Time members (and the value of nullable time members). Defaults to NullTimeToUnixZero.
+// NullTimeError catches NULLs in columns that should never contain them. The mode is looked up for every NULL time, so it cannot be changed while rows are being scanned.
+var NullTimeHandling = NullTimeToUnixZero
+
 //-------------------Generic numeric converters and (set)null-------------------
 
-func convUNum[T uint8 | uint16 | uint32 | uint64](in []byte, p upt, bits int) error {
+func convUNum[T uint8 | uint16 | uint32 | uint64](in []byte, p upt, bits, base int) error {
 	if in == nil {
 		*(*T)(p) = 0
-	} else if n, err := strconv.ParseUint(b2s(in), 10, bits); err != nil {
+	} else if n, err := strconv.ParseUint(b2s(numText(in)), base, bits); err != nil {
 		return err
 	} else {
 		*(*T)(p) = T(n)
 	}
 	return nil
 }
-func convINum[T int8 | int16 | int32 | int64](in []byte, p upt, bits int) error {
+func convINum[T int8 | int16 | int32 | int64](in []byte, p upt, bits, base int) error {
 	if in == nil {
 		*(*T)(p) = 0
-	} else if n, err := strconv.ParseInt(b2s(in), 10, bits); err != nil {
+	} else if n, err := strconv.ParseInt(b2s(numText(in)), base, bits); err != nil {
 		return err
 	} else {
 		*(*T)(p) = T(n)
@@ -37,28 +79,94 @@ func convINum[T int8 | int16 | int32 | int64](in []byte, p upt, bits int) error
 func convFloat[T float32 | float64](in []byte, p upt, bits int) error {
 	if in == nil {
 		*(*T)(p) = 0
-	} else if n, err := strconv.ParseFloat(b2s(in), bits); err != nil {
+	} else if n, err := strconv.ParseFloat(b2s(numText(in)), bits); err != nil {
 		return err
 	} else {
 		*(*T)(p) = T(n)
 	}
 	return nil
 }
+
+// isByteSlice returns if the type is a slice of bytes, including typedef derivatives of the slice or its elements (ex: json.RawMessage, sql.RawBytes)
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// isIntegerKind returns if the kind is a signed or unsigned integer (excluding uintptr)
+func isIntegerKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
+
+// intConverterWithBase returns the conversion function for an integer kind that parses in the base
+func intConverterWithBase(k reflect.Kind, base int) converterFunc {
+	const intBits, uintBits = int(unsafe.Sizeof(0) * 8), int(unsafe.Sizeof(uint(0)) * 8)
+	switch k {
+	case reflect.Int:
+		if intBits == 32 {
+			return func(in []byte, p upt) error { return convINum[int32](in, p, 32, base) }
+		}
+		return func(in []byte, p upt) error { return convINum[int64](in, p, 64, base) }
+	case reflect.Int8:
+		return func(in []byte, p upt) error { return convINum[int8](in, p, 8, base) }
+	case reflect.Int16:
+		return func(in []byte, p upt) error { return convINum[int16](in, p, 16, base) }
+	case reflect.Int32:
+		return func(in []byte, p upt) error { return convINum[int32](in, p, 32, base) }
+	case reflect.Int64:
+		return func(in []byte, p upt) error { return convINum[int64](in, p, 64, base) }
+	case reflect.Uint:
+		if uintBits == 32 {
+			return func(in []byte, p upt) error { return convUNum[uint32](in, p, 32, base) }
+		}
+		return func(in []byte, p upt) error { return convUNum[uint64](in, p, 64, base) }
+	case reflect.Uint8:
+		return func(in []byte, p upt) error { return convUNum[uint8](in, p, 8, base) }
+	case reflect.Uint16:
+		return func(in []byte, p upt) error { return convUNum[uint16](in, p, 16, base) }
+	case reflect.Uint32:
+		return func(in []byte, p upt) error { return convUNum[uint32](in, p, 32, base) }
+	case reflect.Uint64:
+		return func(in []byte, p upt) error { return convUNum[uint64](in, p, 64, base) }
+	}
+	return nil
+}
+
+func numText(in []byte) []byte {
+	if !TrimNumericSpaces {
+		return in
+	}
+
+	start, end := 0, len(in)
+	for start < end && isASCIISpace(in[start]) {
+		start++
+	}
+	for end > start && isASCIISpace(in[end-1]) {
+		end--
+	}
+	return in[start:end]
+}
+func isASCIISpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
 func null(in []byte, p upt) []byte {
+	if EmptyStringIsNull && len(in) == 0 {
+		in = nil
+	}
 	(*nt.NullInherit)(p).IsNull = in == nil
 	return in
 }
 
 //-------------------Conversion function for all scalar types-------------------
 
-func convUint8(in []byte, p upt) error    { return convUNum[uint8](in, p, 8) }
-func convUint16(in []byte, p upt) error   { return convUNum[uint16](in, p, 16) }
-func convUint32(in []byte, p upt) error   { return convUNum[uint32](in, p, 32) }
-func convUint64(in []byte, p upt) error   { return convUNum[uint64](in, p, 64) }
-func convInt8(in []byte, p upt) error     { return convINum[int8](in, p, 8) }
-func convInt16(in []byte, p upt) error    { return convINum[int16](in, p, 16) }
-func convInt32(in []byte, p upt) error    { return convINum[int32](in, p, 32) }
-func convInt64(in []byte, p upt) error    { return convINum[int64](in, p, 64) }
+func convUint8(in []byte, p upt) error    { return convUNum[uint8](in, p, 8, 10) }
+func convUint16(in []byte, p upt) error   { return convUNum[uint16](in, p, 16, 10) }
+func convUint32(in []byte, p upt) error   { return convUNum[uint32](in, p, 32, 10) }
+func convUint64(in []byte, p upt) error   { return convUNum[uint64](in, p, 64, 10) }
+func convInt8(in []byte, p upt) error     { return convINum[int8](in, p, 8, 10) }
+func convInt16(in []byte, p upt) error    { return convINum[int16](in, p, 16, 10) }
+func convInt32(in []byte, p upt) error    { return convINum[int32](in, p, 32, 10) }
+func convInt64(in []byte, p upt) error    { return convINum[int64](in, p, 64, 10) }
+func convDuration(in []byte, p upt) error { return convINum[int64](in, p, 64, 10) }
 func convFloat32(in []byte, p upt) error  { return convFloat[float32](in, p, 32) }
 func convFloat64(in []byte, p upt) error  { return convFloat[float64](in, p, 64) }
 func convString(in []byte, p upt) error   { *(*string)(p) = string(in); return nil }
@@ -74,23 +182,73 @@ func convByteArray(in []byte, p upt) error {
 	return nil
 }
 func convBool(in []byte, p upt) error {
-	if in == nil {
-		*(*bool)(p) = false
+	if len(in) == 1 && in[0] >= '0' && in[0] <= '9' {
+		*(*bool)(p) = in[0] != '0'
 	} else {
-		*(*bool)(p) = in[0] == '1'
+		*(*bool)(p) = len(in) != 0 && isBoolTextTrue(in)
 	}
 	return nil
 }
-func convTime(in []byte, p upt) error {
-	//Null sets to timestamp=0
+
+// isBoolTextTrue returns if the text is truthy: a non-zero integer (ex: “1”, “-1”, “2”), or (case-insensitive) “true”, “t”, “yes”, or “y”. Everything else is false.
+func isBoolTextTrue(in []byte) bool {
+	if n, err := strconv.ParseInt(b2s(numText(in)), 10, 64); err == nil {
+		return n != 0
+	} else if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return true
+	}
+	for _, str := range [...]string{"true", "t", "yes", "y"} {
+		if strings.EqualFold(b2s(in), str) {
+			return true
+		}
+	}
+	return false
+}
+func convTime(in []byte, p upt) error { return convTimeWith(in, p, nil, time.UTC) }
+
+// ConvertTime converts a column into a time the same way a time.Time member is converted (nil is NULL). Text times without a time zone are parsed in loc (and timestamps are converted to it), which defaults to UTC.
+func ConvertTime(in []byte, loc *time.Location) (time.Time, error) {
+	var t time.Time
+	err := convTimeWith(in, upt(&t), nil, cond(loc == nil, time.UTC, loc))
+	return t, err
+}
+
+// setNullTime sets a time for NULL according to NullTimeHandling. The value of nullable members (isNullable) is set to time.Time{} instead of returning an error.
+func setNullTime(p upt, loc *time.Location, isNullable bool) error {
+	switch NullTimeHandling {
+	case NullTimeToUnixZero:
+		*(*time.Time)(p) = time.Unix(0, 0).In(loc)
+	case NullTimeToZeroTime:
+		*(*time.Time)(p) = time.Time{}
+	default:
+		if !isNullable {
+			return errors.New("NULL is not allowed")
+		}
+		*(*time.Time)(p) = time.Time{}
+	}
+	return nil
+}
+
+// convTimeWith is convTime with custom layouts, which are tried before the timestamp and default layout parsing, and the location that text times without a time zone are parsed in (and that timestamps are converted to). See ReaderOptions
+func convTimeWith(in []byte, p upt, layouts []string, loc *time.Location) error {
+	//Null is set according to NullTimeHandling
 	if in == nil {
-		*(*time.Time)(p) = time.Unix(0, 0).UTC()
-		return nil
+		return setNullTime(p, loc, false)
+	}
+
+	//Parse with the custom layouts first, so all-digit layouts (ex: “20060102”) are not read as timestamps
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, b2s(in), loc); err == nil {
+			*(*time.Time)(p) = t
+			return nil
+		}
 	}
 
-	//If there are only digits and an optional single decimal place, parse the number as a timestamp (with optional fractional seconds)
+	//If there are only digits and an optional single decimal place (with an optional leading negative sign), parse the number as a timestamp (with optional fractional seconds)
+	isNegative := len(in) > 1 && in[0] == '-'
+	num := in[cond(isNegative, 1, 0):]
 	dotLoc, isValidFloat := -1, true
-	for loc, r := range in {
+	for loc, r := range num {
 		if r >= '0' && r <= '9' {
 			continue
 		}
@@ -101,11 +259,16 @@ func convTime(in []byte, p upt) error {
 		dotLoc = loc
 	}
 	if isValidFloat {
+		//Both sides of the decimal place must have digits (ex: “.”, “1.”, and “.5” are not valid)
+		if len(num) == 0 || dotLoc == 0 || dotLoc == len(num)-1 {
+			return fmt.Errorf("Invalid timestamp “%s”", in)
+		}
+
 		//Get the fractional part
 		fractionalSeconds := int64(0)
 		if dotLoc != -1 {
 			nanoBuff := []byte{'0', '0', '0', '0', '0', '0', '0', '0', '0'} //Maximum number of nanoseconds in a second is 9 digits
-			frac := b2s(in)[dotLoc+1:]
+			frac := b2s(num)[dotLoc+1:]
 			if len(frac) > len(nanoBuff) {
 				frac = frac[0:len(nanoBuff)]
 			}
@@ -117,27 +280,63 @@ func convTime(in []byte, p upt) error {
 			}
 		} else {
 			//Reset the dot location to the end of the number
-			dotLoc = len(in)
+			dotLoc = len(num)
 		}
 
 		//Get the integral part
-		if integralSeconds, err := strconv.ParseInt(b2s(in)[0:dotLoc], 10, 64); err != nil {
+		if integralSeconds, err := strconv.ParseInt(b2s(num)[0:dotLoc], 10, 64); err != nil {
 			return err
+		} else if isNegative {
+			*(*time.Time)(p) = time.Unix(-integralSeconds, -fractionalSeconds).In(loc)
 		} else {
-			*(*time.Time)(p) = time.Unix(integralSeconds, fractionalSeconds).UTC()
+			*(*time.Time)(p) = time.Unix(integralSeconds, fractionalSeconds).In(loc)
+		}
+		return nil
+	}
+
+	//Handle mysql zero dates
+	if isZeroDate(in) {
+		switch ZeroDateHandling {
+		case ZeroDateToZeroTime:
+			*(*time.Time)(p) = time.Time{}
+		case ZeroDateToUnixZero:
+			*(*time.Time)(p) = time.Unix(0, 0).In(loc)
+		default:
+			return errors.New("Zero date is not allowed")
 		}
 		return nil
 	}
 
 	//Parse as mysql time
-	if t, err := time.Parse(`2006-01-02 15:04:05.99999`, b2s(in)); err != nil {
-		return err
-	} else {
-		*(*time.Time)(p) = t
+	t, err := time.ParseInLocation(`2006-01-02 15:04:05.999999999`, b2s(in), loc)
+	if err != nil {
+		//Fall back to RFC3339 (with optional fractional seconds), which keeps its own time zone (ex: text from outside the database)
+		if len(in) <= 10 || in[10] != 'T' {
+			return err
+		} else if rfcT, rfcErr := time.ParseInLocation(time.RFC3339Nano, b2s(in), loc); rfcErr != nil {
+			return err
+		} else {
+			t = rfcT
+		}
 	}
+	*(*time.Time)(p) = t
 	return nil
 }
 
+// isZeroDate returns if the text is a mysql zero date (“0000-00-00” optionally followed by a zero time)
+func isZeroDate(in []byte) bool {
+	const zeroDate = "0000-00-00"
+	if len(in) < len(zeroDate) || b2s(in[0:len(zeroDate)]) != zeroDate {
+		return false
+	}
+	for _, c := range in[len(zeroDate):] {
+		if c != '0' && c != ' ' && c != ':' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
 // ---------------Conversion function for all NULLABLE scalar types--------------
 //I had to get a bit aggressive with name shortening methods below to keep everything on 1 line
 
@@ -155,4 +354,272 @@ func cvNS(b []byte, p upt) error   { return convString(null(b, p), upt(&(*nt.Nul
 func cvNRB(b []byte, p upt) error  { return convRawBytes(null(b, p), upt(&(*nt.NullRawBytes)(p).Val)) }
 func cvNBA(b []byte, p upt) error  { return convByteArray(null(b, p), upt(&(*nt.NullByteArray)(p).Val)) }
 func cvNB(b []byte, p upt) error   { return convBool(null(b, p), upt(&(*nt.NullBool)(p).Val)) }
-func cvNT(b []byte, p upt) error   { return convTime(null(b, p), upt(&(*nt.NullTime)(p).Val)) }
+func cvNT(b []byte, p upt) error   { return convNTWith(b, p, time.UTC, convTime) }
+
+// convNTWith converts into a nulltypes.NullTime (or a layout match) via the time converter, for which NULL is not an error (see setNullTime)
+func convNTWith(in []byte, p upt, loc *time.Location, convT converterFunc) error {
+	if in = null(in, p); in == nil {
+		return setNullTime(upt(&(*nt.NullTime)(p).Val), loc, true)
+	}
+	return convT(in, upt(&(*nt.NullTime)(p).Val))
+}
+
+//-------------------Conversion function for sync/atomic types------------------
+
+func convAtomic[T any](in []byte, conv converterFunc, store func(T)) error {
+	var v T
+	if err := conv(in, upt(&v)); err != nil {
+		return err
+	}
+	store(v)
+	return nil
+}
+
+func cvAI32(b []byte, p upt) error { return convAtomic(b, convInt32, (*atomic.Int32)(p).Store) }
+func cvAI64(b []byte, p upt) error { return convAtomic(b, convInt64, (*atomic.Int64)(p).Store) }
+func cvAU32(b []byte, p upt) error { return convAtomic(b, convUint32, (*atomic.Uint32)(p).Store) }
+func cvAU64(b []byte, p upt) error { return convAtomic(b, convUint64, (*atomic.Uint64)(p).Store) }
+func cvAB(b []byte, p upt) error   { return convAtomic(b, convBool, (*atomic.Bool)(p).Store) }
+
+//------------Conversion functions for database/sql’s Null* structures-----------
+
+// convSQLNull converts into a database/sql Null* structure (ex: sql.NullString), which all have the layout {VALUE T; Valid bool}. NULL sets the value to its zero value.
+func convSQLNull[T any](in []byte, p upt, conv converterFunc) error {
+	if EmptyStringIsNull && len(in) == 0 {
+		in = nil
+	}
+	n := (*struct {
+		V     T
+		Valid bool
+	})(p)
+	if n.Valid = in != nil; !n.Valid {
+		var zero T
+		n.V = zero
+		return nil
+	}
+	return conv(in, upt(&n.V))
+}
+
+func cvSNS(b []byte, p upt) error   { return convSQLNull[string](b, p, convString) }
+func cvSNI16(b []byte, p upt) error { return convSQLNull[int16](b, p, convInt16) }
+func cvSNI32(b []byte, p upt) error { return convSQLNull[int32](b, p, convInt32) }
+func cvSNI64(b []byte, p upt) error { return convSQLNull[int64](b, p, convInt64) }
+func cvSNU8(b []byte, p upt) error  { return convSQLNull[uint8](b, p, convUint8) }
+func cvSNF64(b []byte, p upt) error { return convSQLNull[float64](b, p, convFloat64) }
+func cvSNB(b []byte, p upt) error   { return convSQLNull[bool](b, p, convBool) }
+func cvSNT(b []byte, p upt) error   { return convSQLNull[time.Time](b, p, convTime) }
+
+//--------------------Conversion functions for custom interfaces----------------
+
+// BytesScanner can be implemented (with a pointer receiver) by types to receive the raw column bytes directly, without the “any” boxing of sql.Scanner.
+// in is nil for NULL. in is only valid until the next scan, so it must be copied if it is kept.
+type BytesScanner interface {
+	ScanBytes(in []byte) error
+}
+
+// makeBytesScannerConverter creates a converter for type t (whose pointer must implement BytesScanner). The interface is only built once so no allocations or reflection occur during conversion.
+func makeBytesScannerConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(BytesScanner)
+	return func(in []byte, p upt) error {
+		return pointer2Interface(proto, unsafe.Pointer(p)).ScanBytes(in)
+	}
+}
+
+// BytesConsumer can be implemented (with a pointer receiver) by types that decode composite/packed columns (ex: binary arrays). ConsumeBytes returns the number of bytes of the column it consumed, and the member receives an error if the column was not fully consumed.
+// in is nil for NULL. in is only valid until the next scan, so it must be copied if it is kept.
+type BytesConsumer interface {
+	ConsumeBytes(in []byte) (int, error)
+}
+
+// makeBytesConsumerConverter creates a converter for type t (whose pointer must implement BytesConsumer) that returns an error if the column was not fully consumed
+func makeBytesConsumerConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(BytesConsumer)
+	return consumeAll(func(in []byte, p upt) (int, error) {
+		return pointer2Interface(proto, unsafe.Pointer(p)).ConsumeBytes(in)
+	})
+}
+
+// makeSQLScannerConverter creates a converter for type t (whose pointer must implement sql.Scanner) that passes the column to Scan() as a []byte (or nil for NULL). The interface is only built once so no reflection occurs during conversion.
+func makeSQLScannerConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(sql.Scanner)
+	return func(in []byte, p upt) error {
+		if in == nil {
+			return pointer2Interface(proto, unsafe.Pointer(p)).Scan(nil)
+		}
+		return pointer2Interface(proto, unsafe.Pointer(p)).Scan(in)
+	}
+}
+
+// makeTextUnmarshalerConverter creates a converter for type t (whose pointer must implement encoding.TextUnmarshaler) that passes the column to UnmarshalText(). NULL sets the member to its zero value.
+func makeTextUnmarshalerConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(encoding.TextUnmarshaler)
+	return func(in []byte, p upt) error {
+		if in == nil {
+			reflect.NewAt(t, unsafe.Pointer(p)).Elem().Set(reflect.Zero(t))
+			return nil
+		}
+		if err := pointer2Interface(proto, unsafe.Pointer(p)).UnmarshalText(in); err != nil {
+			return fmt.Errorf("%s.UnmarshalText: %s", t.String(), err.Error())
+		}
+		return nil
+	}
+}
+
+// makeBinaryUnmarshalerConverter creates a converter for type t (whose pointer must implement encoding.BinaryUnmarshaler) that passes a copy of the column to UnmarshalBinary() (as implementations may retain it). NULL sets the member to its zero value.
+func makeBinaryUnmarshalerConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(encoding.BinaryUnmarshaler)
+	return func(in []byte, p upt) error {
+		if in == nil {
+			reflect.NewAt(t, unsafe.Pointer(p)).Elem().Set(reflect.Zero(t))
+			return nil
+		}
+		if err := pointer2Interface(proto, unsafe.Pointer(p)).UnmarshalBinary(append([]byte{}, in...)); err != nil {
+			return fmt.Errorf("%s.UnmarshalBinary: %s", t.String(), err.Error())
+		}
+		return nil
+	}
+}
+
+/*
+makeSQLNullConverter creates a converter for database/sql’s generic Null[T] (Go 1.22+), which sets Valid and converts into V via the standard converter for T. nil is returned if t is not a Null[T] or T is not supported.
+The type is detected by its name and members so this does not require Go 1.22 to compile.
+*/
+func makeSQLNullConverter(t reflect.Type) converterFunc {
+	if t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null[") || t.NumField() != 2 {
+		return nil
+	}
+	vFld, validFld := t.Field(0), t.Field(1)
+	if vFld.Name != "V" || validFld.Name != "Valid" || validFld.Type.Kind() != reflect.Bool {
+		return nil
+	}
+	vConv, sff := scalarToConversionFunc(vFld.Type)
+	if vConv == nil || sff&sffIsRawBytes != 0 {
+		return nil
+	}
+
+	vType, vOffset, validOffset := vFld.Type, vFld.Offset, validFld.Offset
+	return func(in []byte, p upt) error {
+		if EmptyStringIsNull && len(in) == 0 {
+			in = nil
+		}
+		*(*bool)(unsafe.Add(unsafe.Pointer(p), validOffset)) = in != nil
+		vp := unsafe.Add(unsafe.Pointer(p), vOffset)
+		if in == nil {
+			reflect.NewAt(vType, vp).Elem().Set(reflect.Zero(vType))
+			return nil
+		}
+		return vConv(in, upt(vp))
+	}
+}
+
+/*
+makeNullTypeConverter creates a converter for the generic nulltypes.Null[T], which sets IsNull and converts into Val via the standard converter for T. nil is returned if t is not a nulltypes.Null[T] or T is not supported.
+The returned flags keep the flags of T that ReaderOptions and the singular ScanRow functions act on, as the layout matches the concrete nulltypes (ex: Null[string] and NullString).
+*/
+func makeNullTypeConverter(t reflect.Type) (converterFunc, structFieldFlags) {
+	if t.PkgPath() != lookupType.nullInherit.PkgPath() || !strings.HasPrefix(t.Name(), "Null[") || t.NumField() != 2 {
+		return nil, sffNoFlags
+	}
+	valFld := t.Field(1)
+	if t.Field(0).Type != lookupType.nullInherit || valFld.Name != "Val" {
+		return nil, sffNoFlags
+	}
+	valConv, sff := scalarToConversionFunc(valFld.Type)
+	if valConv == nil {
+		return nil, sffNoFlags
+	} else if sff&sffIsTime != 0 {
+		return cvNT, sffIsNullable | sffIsTime //NULL is not an error for the value of nullable times
+	}
+
+	valOffset := valFld.Offset
+	return func(in []byte, p upt) error {
+		return valConv(null(in, p), upt(unsafe.Add(unsafe.Pointer(p), valOffset)))
+	}, sffIsNullable | sff&(sffDefaultConverters|sffIsRawBytes)
+}
+
+/*
+makeFixedArrayConverter creates a converter for fixed width arrays of bytes ([N]byte) or runes ([N]rune), ex: CHAR(N) columns. nil is returned if t is not one of these.
+The column is copied (or decoded as UTF-8 for runes) into the array and the remaining elements are zeroed. An error is returned if the column has more than N elements. NULL zeroes the array.
+*/
+func makeFixedArrayConverter(t reflect.Type) converterFunc {
+	n := t.Len()
+	switch t.Elem().Kind() {
+	case reflect.Uint8:
+		return func(in []byte, p upt) error {
+			if len(in) > n {
+				return fmt.Errorf("Column length (%d) is greater than array length (%d)", len(in), n)
+			}
+			out := unsafe.Slice((*byte)(p), n)
+			for i := copy(out, in); i < n; i++ {
+				out[i] = 0
+			}
+			return nil
+		}
+	case reflect.Int32:
+		return func(in []byte, p upt) error {
+			if numRunes := utf8.RuneCount(in); numRunes > n {
+				return fmt.Errorf("Column rune count (%d) is greater than array length (%d)", numRunes, n)
+			}
+			out := unsafe.Slice((*rune)(p), n)
+			i := 0
+			for len(in) != 0 {
+				r, size := utf8.DecodeRune(in)
+				out[i], in, i = r, in[size:], i+1
+			}
+			for ; i < n; i++ {
+				out[i] = 0
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// makeWriterConverter creates a converter for type t (whose pointer must implement io.Writer) that writes the column into the member. NULL writes nothing.
+func makeWriterConverter(t reflect.Type) converterFunc {
+	proto := reflect.New(t).Interface().(io.Writer)
+	return func(in []byte, p upt) error {
+		return writeColumn(pointer2Interface(proto, unsafe.Pointer(p)), in)
+	}
+}
+
+// convWriter writes the column into an io.Writer member, which must be set. NULL writes nothing.
+func convWriter(in []byte, p upt) error {
+	if w := *(*io.Writer)(p); w == nil {
+		return errors.New("Writer not initialized")
+	} else {
+		return writeColumn(w, in)
+	}
+}
+
+func writeColumn(w io.Writer, in []byte) error {
+	if in == nil {
+		return nil
+	}
+	_, err := w.Write(in)
+	return err
+}
+
+//-------------------Composite (packed) column converter helpers-----------------
+
+// consumerFunc is a converter for composite/packed columns which returns the number of bytes of the column it consumed
+type consumerFunc func(in []byte, p upt) (int, error)
+
+// consumeAll turns a consumerFunc into a converterFunc that returns an error if the column was not fully consumed
+func consumeAll(f consumerFunc) converterFunc {
+	return func(in []byte, p upt) error {
+		if n, err := f(in, p); err != nil {
+			return err
+		} else if n < len(in) {
+			return trailingBytesError(len(in) - n)
+		}
+		return nil
+	}
+}
+
+// trailingBytesError is returned when a composite column converter did not consume all the bytes in its column. The member’s name is added by RowReader.convert() (see ErrorFormatter).
+type trailingBytesError int
+
+func (e trailingBytesError) Error() string {
+	return fmt.Sprintf("%d trailing bytes in column were not consumed", int(e))
+}
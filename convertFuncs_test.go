@@ -0,0 +1,99 @@
+package gofastersql
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvTimeMalformed(t *testing.T) {
+	for _, in := range []string{"", ".", "-", "-.", "1.", ".5", "-.5", "-1.", "..5", "1..5", "1.2.3", "--1", strings.Repeat("9", 100), "1." + strings.Repeat("9", 100) + "x", "2024-01-02T15:04:05", "2024-01-02T25:04:05Z"} {
+		var tm time.Time
+		if err := convTime([]byte(in), upt(&tm)); err == nil {
+			t.Fatalf("Expected an error for “%s”, got %s", in, tm.String())
+		}
+	}
+
+	for in, expected := range map[string]time.Time{
+		"5":                                   time.Unix(5, 0),
+		"-5":                                  time.Unix(-5, 0),
+		"1.5":                                 time.Unix(1, 500_000_000),
+		"-1.5":                                time.Unix(-1, -500_000_000),
+		"1." + strings.Repeat("1", 50):        time.Unix(1, 111_111_111),
+		"2001-02-03 04:05:06.7":               time.Date(2001, 2, 3, 4, 5, 6, 700_000_000, time.UTC),
+		"2024-01-02T15:04:05Z":                time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		"2024-01-02T15:04:05.123456789+07:00": time.Date(2024, 1, 2, 8, 4, 5, 123_456_789, time.UTC),
+	} {
+		var tm time.Time
+		if err := convTime([]byte(in), upt(&tm)); err != nil {
+			t.Fatalf("Unexpected error for “%s”: %s", in, err.Error())
+		} else if !tm.Equal(expected) {
+			t.Fatalf("Time for “%s” does not match %s!=%s", in, tm.String(), expected.String())
+		}
+	}
+}
+
+func TestConvTimeNull(t *testing.T) {
+	defer func(mode NullTimeMode) { NullTimeHandling = mode }(NullTimeHandling)
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	for mode, expected := range map[NullTimeMode]time.Time{
+		NullTimeToUnixZero: time.Unix(0, 0).In(loc),
+		NullTimeToZeroTime: {},
+	} {
+		NullTimeHandling = mode
+		if tm, err := ConvertTime(nil, loc); err != nil {
+			t.Fatalf("Unexpected error for mode %d: %s", mode, err.Error())
+		} else if tm != expected {
+			t.Fatalf("Time for mode %d does not match %s!=%s", mode, tm.String(), expected.String())
+		}
+	}
+
+	NullTimeHandling = NullTimeError
+	if _, err := ConvertTime(nil, loc); err == nil {
+		t.Fatal("Expected an error for NULL")
+	}
+	var nt struct {
+		IsNull bool
+		Val    time.Time
+	}
+	if err := cvNT(nil, upt(&nt)); err != nil || !nt.IsNull || !nt.Val.IsZero() {
+		t.Fatalf("Nullable time did not become NULL: %v %v", err, nt)
+	}
+}
+
+func TestConvTimeWithDigitLayouts(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	for in, expected := range map[string]time.Time{
+		"20240105":            time.Date(2024, 1, 5, 0, 0, 0, 0, loc),
+		"20240105123456":      time.Date(2024, 1, 5, 12, 34, 56, 0, loc),
+		"5":                   time.Unix(5, 0), //Matches no layout, so it is still a timestamp
+		"2024-01-05 01:02:03": time.Date(2024, 1, 5, 1, 2, 3, 0, loc),
+	} {
+		var tm time.Time
+		if err := convTimeWith([]byte(in), upt(&tm), []string{"20060102", "20060102150405"}, loc); err != nil {
+			t.Fatalf("Unexpected error for “%s”: %s", in, err.Error())
+		} else if !tm.Equal(expected) {
+			t.Fatalf("Time for “%s” does not match %s!=%s", in, tm.String(), expected.String())
+		}
+	}
+}
+
+func FuzzConvTime(f *testing.F) {
+	for _, seed := range []string{"", ".", "1.", "..5", "-", "-1.5", "123", "1.123456789123", strings.Repeat("1", 30), "2001-02-03 04:05:06", "0000-00-00 00:00:00", "2001-02-03 04:05:06.123456789"} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, in []byte) {
+		var tm time.Time
+		err := convTime(in, upt(&tm))
+
+		//Plain integers must match their timestamp exactly
+		if n, parseErr := strconv.ParseInt(string(in), 10, 64); parseErr == nil && in[0] != '+' {
+			if err != nil {
+				t.Fatalf("Unexpected error for “%s”: %s", in, err.Error())
+			} else if !tm.Equal(time.Unix(n, 0)) {
+				t.Fatalf("Time for “%s” does not match %s!=%s", in, tm.String(), time.Unix(n, 0).String())
+			}
+		}
+	})
+}
@@ -0,0 +1,25 @@
+//go:build gofastersql_debug
+
+//Phase timing instrumentation for RowReaders (only compiled with the gofastersql_debug build tag)
+
+package gofastersql
+
+import (
+	"time"
+)
+
+type debugTimings struct {
+	t ScanTimings
+}
+type debugMark time.Time
+
+func debugStart() debugMark { return debugMark(time.Now()) }
+
+func (d *debugTimings) addScan(m debugMark) {
+	d.t.Scan += time.Since(time.Time(m))
+	d.t.NumScans++
+}
+func (d *debugTimings) addPointers(m debugMark) { d.t.Pointers += time.Since(time.Time(m)) }
+func (d *debugTimings) addConvert(m debugMark)  { d.t.Convert += time.Since(time.Time(m)) }
+func (d *debugTimings) get() ScanTimings        { return d.t }
+func (d *debugTimings) reset()                  { d.t = ScanTimings{} }
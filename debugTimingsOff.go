@@ -0,0 +1,16 @@
+//go:build !gofastersql_debug
+
+//Phase timing instrumentation for RowReaders compiles to nothing without the gofastersql_debug build tag
+
+package gofastersql
+
+type debugTimings struct{}
+type debugMark struct{}
+
+func debugStart() debugMark { return debugMark{} }
+
+func (d *debugTimings) addScan(debugMark)     {}
+func (d *debugTimings) addPointers(debugMark) {}
+func (d *debugTimings) addConvert(debugMark)  {}
+func (d *debugTimings) get() ScanTimings      { return ScanTimings{} }
+func (d *debugTimings) reset()                {}
@@ -0,0 +1,140 @@
+//Rows whose columns are only converted when requested
+
+package gofastersql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrNeedsParentStruct is the error given by LazyRow.Scan for members that can only be converted inside their structure
+var ErrNeedsParentStruct = errors.New("Member can only be converted inside its structure")
+
+// LazyRow holds a copy of a row’s columns, which are only converted when requested through its getters. Members are requested by the names used to match columns (see RowReaderNamed), or by their base name if it is unambiguous. See RowReader.ScanLazy()
+type LazyRow struct {
+	model *lazyModel
+	cols  [][]byte //Copies of the columns (nil for NULL)
+}
+
+// lazyModel holds the member lookups shared by all the LazyRows of a RowReader
+type lazyModel struct {
+	fields   []structField
+	colIndex map[string]int //The column index for each member name
+}
+
+/*
+ScanLazy copies the current row’s columns into a LazyRow without converting them, so only the members that are requested from it are converted (ex: a wide row where only a few columns are read).
+The LazyRow is independent of the rows, so it stays valid after the next scan. This does not call rows.Next() or rows.Close().
+*/
+func (rr *RowReader) ScanLazy(rows *sql.Rows) (*LazyRow, error) {
+	if err := rr.scanRaw(rows); err != nil {
+		return nil, err
+	}
+	if rr.lazy == nil {
+		rr.lazy = newLazyModel(rr.sm)
+	}
+
+	return &LazyRow{rr.lazy, copyRawBytes(rr.rawBytesArr)}, nil
+}
+
+// newLazyModel creates the member lookups for the fields (in column order). Full names take precedence, and base names are only added if they are unambiguous.
+func newLazyModel(sm StructModel) *lazyModel {
+	names, baseNames := sm.fieldNames()
+	colIndex := make(map[string]int, len(names)*2)
+	baseNameCount := make(map[string]int, len(names))
+	for i, sf := range sm.fields {
+		if sf.rType != nil {
+			baseNameCount[baseNames[i]]++
+		}
+	}
+	for i, sf := range sm.fields {
+		if sf.rType != nil && baseNameCount[baseNames[i]] == 1 && len(baseNames[i]) != 0 {
+			colIndex[baseNames[i]] = i
+		}
+	}
+	for i, sf := range sm.fields {
+		if sf.rType != nil {
+			colIndex[names[i]] = i
+		}
+	}
+	return &lazyModel{sm.fields, colIndex}
+}
+
+// column returns the column of a member
+func (lr *LazyRow) column(name string) ([]byte, error) {
+	if i, ok := lr.model.colIndex[name]; ok {
+		return lr.cols[i], nil
+	}
+	return nil, fmt.Errorf("Member “%s” not found", name)
+}
+
+// lazyGet converts the column of a member with a conversion function
+func lazyGet[T any](lr *LazyRow, name string, conv converterFunc) (T, error) {
+	var v T
+	col, err := lr.column(name)
+	if err != nil {
+		return v, err
+	} else if err := conv(col, upt(&v)); err != nil {
+		return v, errors.New(ErrorFormatter(name, err))
+	}
+	return v, nil
+}
+
+// IsNull returns if the member’s column is NULL
+func (lr *LazyRow) IsNull(name string) (bool, error) {
+	col, err := lr.column(name)
+	return col == nil, err
+}
+
+// Bytes returns the member’s column as is (nil for NULL). The returned slice must not be modified.
+func (lr *LazyRow) Bytes(name string) ([]byte, error) { return lr.column(name) }
+
+// String returns the member’s column as a string (empty for NULL)
+func (lr *LazyRow) String(name string) (string, error) {
+	return lazyGet[string](lr, name, convString)
+}
+
+// Int returns the member’s column converted to an int64 (0 for NULL)
+func (lr *LazyRow) Int(name string) (int64, error) { return lazyGet[int64](lr, name, convInt64) }
+
+// Uint returns the member’s column converted to a uint64 (0 for NULL)
+func (lr *LazyRow) Uint(name string) (uint64, error) { return lazyGet[uint64](lr, name, convUint64) }
+
+// Float returns the member’s column converted to a float64 (0 for NULL)
+func (lr *LazyRow) Float(name string) (float64, error) {
+	return lazyGet[float64](lr, name, convFloat64)
+}
+
+// Bool returns the member’s column converted to a bool (see the type list in the package documentation)
+func (lr *LazyRow) Bool(name string) (bool, error) { return lazyGet[bool](lr, name, convBool) }
+
+// Time returns the member’s column converted to a time.Time (see the type list in the package documentation)
+func (lr *LazyRow) Time(name string) (time.Time, error) {
+	return lazyGet[time.Time](lr, name, convTime)
+}
+
+/*
+Scan converts the member’s column into out with the member’s conversion function (including its gfs tag options). out must be a pointer to the member’s type.
+Members whose conversion accesses other members of their structure (gfs:"resolve", and members copied by gfs:"copyof") return ErrNeedsParentStruct, as out is not inside the structure.
+*/
+func (lr *LazyRow) Scan(name string, out any) error {
+	col, err := lr.column(name)
+	if err != nil {
+		return err
+	}
+	sf := lr.model.fields[lr.model.colIndex[name]]
+	if sf.flags&sffNeedsParent != 0 {
+		return ErrNeedsParentStruct
+	} else if t := reflect.TypeOf(out); t == nil || t.Kind() != reflect.Pointer || t.Elem() != sf.rType {
+		return fmt.Errorf("out type is incorrect (%s)!=(*%s)", fmt.Sprint(t), sf.rType.String())
+	} else if reflect.ValueOf(out).IsNil() {
+		return ErrPointerNotInitialized
+	}
+	if err := sf.converter(col, upt(interface2Pointer(out))); err != nil {
+		return errors.New(ErrorFormatter(name, err))
+	}
+	return nil
+}
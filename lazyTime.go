@@ -0,0 +1,43 @@
+//Time holder that is only parsed on demand
+
+package gofastersql
+
+import (
+	"time"
+)
+
+/*
+LazyTime holds the raw text of a time column so its parsing cost is only paid when (and if) LazyTime.Time() is called.
+It accepts the same formats as time.Time members. NULL is stored as an empty string.
+
+Declare the member as a LazyTime instead of a time.Time to capture the raw text; there is no tag option to capture the text of a time.Time member into another member.
+As the text is only parsed later, ReaderOptions.TimeLayouts and ReaderOptions.TimeLocation do not apply to LazyTime members. Pass them to LazyTime.TimeWith() instead.
+*/
+type LazyTime struct {
+	Raw string
+}
+
+// Time parses the raw text the same way a time.Time member would have been converted by a RowReader without ReaderOptions (in UTC)
+func (lt LazyTime) Time() (time.Time, error) {
+	return lt.TimeWith(nil, nil)
+}
+
+// TimeWith parses the raw text the same way a time.Time member would have been converted by a RowReader with the ReaderOptions TimeLayouts and TimeLocation (nil for UTC)
+func (lt LazyTime) TimeWith(layouts []string, loc *time.Location) (time.Time, error) {
+	var t time.Time
+	var in []byte
+	if len(lt.Raw) != 0 {
+		in = []byte(lt.Raw)
+	}
+	err := convTimeWith(in, upt(&t), layouts, cond(loc == nil, time.UTC, loc))
+	return t, err
+}
+
+// IsNull returns if the column was NULL (or empty)
+func (lt LazyTime) IsNull() bool {
+	return len(lt.Raw) == 0
+}
+
+func convLazyTime(in []byte, p upt) error {
+	return convString(in, upt(&(*LazyTime)(p).Raw))
+}
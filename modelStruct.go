@@ -4,13 +4,17 @@ package gofastersql
 
 import (
 	"database/sql"
+	"encoding"
 	"errors"
 	"fmt"
 	"github.com/dakusan/gofastersql/nulltypes"
+	"io"
 	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 )
@@ -20,38 +24,63 @@ import (
 // StructModel holds the model of a structure for processing as a RowReader. StructModel is concurrency safe.
 // If requested to model multiple types (or just a non-struct scalar) then a hacky version is used that emulates the array of variables as a single struct with pointers to each variable.
 type StructModel struct {
-	fields   []structField   //The flattened list of members from a recursive structure search
-	pointers []structPointer //Data for structure pointers (recursive)
-	rTypes   []reflect.Type  //The types of the top level structures. Used to confirm RowReader.ScanRow*() function “outPointers” parameters’ types match
-	isSimple bool            //If this is modeling a single structure (not a list of variables)
+	fields    []structField    //The flattened list of members from a recursive structure search
+	pointers  []structPointer  //Data for structure pointers (recursive)
+	rTypes    []reflect.Type   //The types of the top level structures. Used to confirm RowReader.ScanRow*() function “outPointers” parameters’ types match
+	isSimple  bool             //If this is modeling a single structure (not a list of variables)
+	presences []structPresence //Data for structures with presence members (see the “present” gfs tag option)
 }
 type structField struct {
-	offset       uintptr          //The offset of the member in structure pointed at by RowReader.pointers[pointerIndex] (which is derived from StructModel.pointers)
-	converter    converterFunc    //The conversion function
-	pointerIndex int              //The structure index to be used for offset (RowReader.pointers[pointerIndex], which is derived from StructModel.pointers)
-	name         string           //The recursed name of the member
-	baseName     string           //The name of the member
-	isPointer    bool             //If the member is a pointer
-	flags        structFieldFlags //Flags about the member
+	offset        uintptr          //The offset of the member in structure pointed at by RowReader.pointers[pointerIndex] (which is derived from StructModel.pointers)
+	converter     converterFunc    //The conversion function
+	pointerIndex  int              //The structure index to be used for offset (RowReader.pointers[pointerIndex], which is derived from StructModel.pointers)
+	name          string           //The recursed name of the member
+	baseName      string           //The name of the member
+	isPointer     bool             //If the member is a pointer
+	flags         structFieldFlags //Flags about the member
+	rType         reflect.Type     //The type of the member (the type pointed to if isPointer)
+	presenceIndex int              //The index+1 of the innermost structure with a presence member (StructModel.presences) that the member is in. 0 if none
+	dbName        string           //name, with the names in db tags replacing the Go names (see ReaderOptions.DBTagNames)
+	dbBaseName    string           //baseName, with the name in the db tag replacing the Go name (see ReaderOptions.DBTagNames)
+	copier        converterFunc    //Converts the column into the members that copy this member (gfs:"copyof"), which is already included in converter. nil if there are none
 }
 type structPointer struct {
-	parentIndex int     //The structure index to be used for offset (RowReader.pointers[parentIndex], which is derived from StructModel.pointers)
-	offset      uintptr //The offset of the member in structure pointed at by RowReader.pointers[parentIndex] (which is derived from StructModel.pointers)
-	name        string  //The recursed name of the member
+	parentIndex int          //The structure index to be used for offset (RowReader.pointers[parentIndex], which is derived from StructModel.pointers)
+	offset      uintptr      //The offset of the member in structure pointed at by RowReader.pointers[parentIndex] (which is derived from StructModel.pointers)
+	name        string       //The recursed name of the member
+	rType       reflect.Type //The type of the structure pointed to
+	nilOnNull   bool         //If the pointer is set to nil when all of its columns are NULL (and allocated when needed otherwise)
+}
+type structPresence struct {
+	parentIndex  int     //The index+1 of the enclosing structure with a presence member (StructModel.presences). 0 if none
+	pointerIndex int     //The structure index to be used for offset (RowReader.pointers[pointerIndex], which is derived from StructModel.pointers)
+	offset       uintptr //The offset of the bool presence member in structure pointed at by RowReader.pointers[pointerIndex]
 }
 
-type structFieldFlags uint8
+type structFieldFlags uint16
 
 const (
-	sffNoFlags    structFieldFlags = 0
-	sffIsRawBytes structFieldFlags = 1 << (iota - 1) //If the member is a RawBytes type
-	sffIsNullable                                    //If the member is a nulltypes struct
+	sffNoFlags      structFieldFlags = 0
+	sffIsRawBytes   structFieldFlags = 1 << (iota - 1) //If the member is a RawBytes type (or otherwise references the RawBytes, ex: gfs:"stream")
+	sffIsNullable                                      //If the member is a nullable struct (nulltypes, sql.Null* or sql.Null[T])
+	sffIsTime                                          //If the member is a time.Time or nullable time (nulltypes.NullTime, sql.NullTime or a Null[time.Time]) using the default conversion function (which ReaderOptions can replace)
+	sffIsString                                        //If the member is a string or nulltypes.NullString using the default conversion function (which ReaderOptions can replace)
+	sffSharedMember                                    //If the member is converted by more than 1 field, which each only set part of it (ex: both columns of datetime2col)
+	sffSkipNull                                        //If the conversion function is not called when the column is NULL (see ReaderOptions.SkipNullConversions)
+	sffIsInteger                                       //If the member is an integer or nulltypes integer using the default conversion function (which ReaderOptions can replace)
+	sffAllocPointer                                    //If the member is a pointer that is allocated when nil (and left nil on NULL) instead of returning ErrPointerNotInitialized
+	sffNeedsParent                                     //If the conversion function accesses other members of the parent structure (ex: gfs:"resolve" and gfs:"copyof"), so it can only convert into the member inside its structure
+
+	sffDefaultConverters = sffIsTime | sffIsString | sffIsInteger //The flags that are only valid while the member uses the default conversion function
 )
 
 // Store structs for future lookups
 var remStructs = make(map[reflect.Type]StructModel)
 var remLock sync.RWMutex
 
+// Conversion functions registered via RegisterConverter (also guarded by remLock)
+var registeredConverters = make(map[reflect.Type]converterFunc)
+
 //-----------------------Mappings for conversion functions----------------------
 
 type converterFunc func(in []byte, p upt) error
@@ -72,6 +101,21 @@ var nullTypeStructConverters = map[reflect.Type]converterFunc{
 	reflect.TypeOf(nulltypes.NullByteArray{}): cvNBA,
 	reflect.TypeOf(nulltypes.NullBool{}):      cvNB,
 	reflect.TypeOf(nulltypes.NullTime{}):      cvNT,
+	reflect.TypeOf(sql.NullString{}):          cvSNS,
+	reflect.TypeOf(sql.NullInt16{}):           cvSNI16,
+	reflect.TypeOf(sql.NullInt32{}):           cvSNI32,
+	reflect.TypeOf(sql.NullInt64{}):           cvSNI64,
+	reflect.TypeOf(sql.NullByte{}):            cvSNU8,
+	reflect.TypeOf(sql.NullFloat64{}):         cvSNF64,
+	reflect.TypeOf(sql.NullBool{}):            cvSNB,
+	reflect.TypeOf(sql.NullTime{}):            cvSNT,
+}
+var atomicStructConverters = map[reflect.Type]converterFunc{
+	reflect.TypeOf((*atomic.Int32)(nil)).Elem():  cvAI32,
+	reflect.TypeOf((*atomic.Int64)(nil)).Elem():  cvAI64,
+	reflect.TypeOf((*atomic.Uint32)(nil)).Elem(): cvAU32,
+	reflect.TypeOf((*atomic.Uint64)(nil)).Elem(): cvAU64,
+	reflect.TypeOf((*atomic.Bool)(nil)).Elem():   cvAB,
 }
 var scalarConverters = make([]converterFunc, reflect.UnsafePointer) //UnsafePointer is the final enum of reflect.Kind
 func init() {
@@ -101,12 +145,23 @@ func init() {
 	}
 }
 
-var lookupType = struct{ time, nullInherit, byteArray, rawBytes, nullRawBytes reflect.Type }{
+var lookupType = struct{ time, duration, lazyTime, nullInherit, rawBytes, nullRawBytes, nullTime, nullString, sqlNullTime, bytesScanner, bytesConsumer, sqlScanner, textUnmarshaler, binaryUnmarshaler, writer, reader reflect.Type }{
 	reflect.TypeOf(time.Time{}),
+	reflect.TypeOf(time.Duration(0)),
+	reflect.TypeOf(LazyTime{}),
 	reflect.TypeOf(nulltypes.NullInherit{}),
-	reflect.TypeOf([]byte{}),
 	reflect.TypeOf(sql.RawBytes{}),
 	reflect.TypeOf(nulltypes.NullRawBytes{}),
+	reflect.TypeOf(nulltypes.NullTime{}),
+	reflect.TypeOf(nulltypes.NullString{}),
+	reflect.TypeOf(sql.NullTime{}),
+	reflect.TypeOf((*BytesScanner)(nil)).Elem(),
+	reflect.TypeOf((*BytesConsumer)(nil)).Elem(),
+	reflect.TypeOf((*sql.Scanner)(nil)).Elem(),
+	reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*io.Writer)(nil)).Elem(),
+	reflect.TypeOf((*io.Reader)(nil)).Elem(),
 }
 
 //------------------------------Create StructModels-----------------------------
@@ -133,7 +188,12 @@ func ModelStruct(s ...any) (StructModel, error) {
 			}
 			remLock.RUnlock()
 
-			return createStructModelFromStruct(t)
+			//The type is only added here, as the multiple variable path already names the type of each parameter
+			sm, err := createStructModelFromStruct(t)
+			if err != nil {
+				return StructModel{}, fmt.Errorf("%s: %s", t.String(), err.Error())
+			}
+			return sm, nil
 		}
 	}
 
@@ -143,7 +203,8 @@ func ModelStruct(s ...any) (StructModel, error) {
 
 // Function to determine if a struct is considered a scalar type
 func isScalarStruct(t reflect.Type) bool {
-	return nullTypeStructConverters[t] != nil || t == lookupType.time
+	fn, _ := scalarToConversionFunc(t)
+	return fn != nil
 }
 
 // Create a StructModel
@@ -157,7 +218,14 @@ func createStructModelFromStruct(t reflect.Type) (StructModel, error) {
 			numFields += v.NumField() - 1
 			for i := 0; i < v.NumField(); i++ {
 				t := v.Field(i).Type
-				if t.Kind() == reflect.Struct && !isScalarStruct(t) {
+				tags := parseTags(v.Field(i).Tag)
+				if isSkippedField(v.Field(i)) {
+					numFields--
+				} else if n := tags.numColumns(); n != 1 {
+					numFields += n - 1
+				} else if tags.isCollapsed() {
+					continue
+				} else if t.Kind() == reflect.Struct && !isScalarStruct(t) {
 					doCount(t)
 				} else if t.Kind() == reflect.Pointer {
 					if el := t.Elem(); el.Kind() == reflect.Struct && !isScalarStruct(el) {
@@ -171,15 +239,30 @@ func createStructModelFromStruct(t reflect.Type) (StructModel, error) {
 	}
 
 	//Create the structure model
-	ret := StructModel{make([]structField, numFields), make([]structPointer, numStructPointers), []reflect.Type{t}, true}
+	ret := StructModel{make([]structField, numFields), make([]structPointer, numStructPointers), []reflect.Type{t}, true, nil}
 	{
-		var processStruct func(reflect.Type, uintptr, int, string) []string
+		var processStruct func(reflect.Type, uintptr, int, string, string, int) []string
 		fieldPos := 0
 		structPointerPos := 0
-		processStruct = func(v reflect.Type, parentOffset uintptr, parentStructIndex int, parentName string) (retErr []string) {
+		processStruct = func(v reflect.Type, parentOffset uintptr, parentStructIndex int, parentName, parentDBName string, presenceIndex int) (retErr []string) {
+			//Members that are copies of another member’s column (gfs:"copyof") are attached to that member’s field after all members are processed
+			type memberCopy struct {
+				name, copyOf string
+				offset       uintptr
+				fn           converterFunc
+			}
+			var copies []memberCopy
+			copyableFields := make(map[string]int) //Go member name to its index in StructModel.fields
+
 			for i := 0; i < v.NumField(); i++ {
-				//Handle pointers
+				//Ignore skipped members
 				fld := v.Field(i)
+				if isSkippedField(fld) {
+					continue
+				}
+				name, dbName := fld.Name, dbTagName(fld)
+
+				//Handle pointers
 				fldType := fld.Type
 				isPointer := fldType.Kind() == reflect.Pointer
 				if isPointer {
@@ -187,35 +270,122 @@ func createStructModelFromStruct(t reflect.Type) (StructModel, error) {
 				}
 
 				//Get the function pointer for the type
+				tags := parseTags(fld.Tag)
 				fn, sff := scalarToConversionFunc(fldType)
-				if fn == nil && fldType.Kind() == reflect.Struct {
+				if fn == nil && fldType.Kind() == reflect.Struct && !tags.isCollapsed() {
+					//Handle the tag options for structures
+					nilOnNull, presentName, err := tags.structOptions(isPointer)
+					if err != nil {
+						retErr = append(retErr, fmt.Sprintf("%s%s: %s", parentName, name, err.Error()))
+					}
+
+					//Structures with a presence member add their StructModel.presences
+					childPresenceIndex := presenceIndex
+					if len(presentName) != 0 {
+						if presentFld, ok := v.FieldByName(presentName); !ok || len(presentFld.Index) != 1 || presentFld.Type.Kind() != reflect.Bool || !isSkippedField(presentFld) {
+							retErr = append(retErr, fmt.Sprintf("%s%s: Presence member “%s” must be a bool member of the same structure tagged with gfs:\"-\"", parentName, name, presentName))
+						} else {
+							ret.presences = append(ret.presences, structPresence{presenceIndex, parentStructIndex, parentOffset + presentFld.Offset})
+							childPresenceIndex = len(ret.presences)
+						}
+					}
+
 					//Pointers to structures need to add their StructModel.pointers and redirect appropriately
 					offset, structIndex := parentOffset+fld.Offset, parentStructIndex
 					if isPointer {
-						ret.pointers[structPointerPos] = structPointer{parentStructIndex, parentOffset + fld.Offset, parentName + fld.Name}
+						ret.pointers[structPointerPos] = structPointer{parentStructIndex, parentOffset + fld.Offset, parentName + name, fldType, nilOnNull}
 						structPointerPos++
 						offset, structIndex = 0, structPointerPos //structIndex is +1 what you'd expect because RowReader.pointers[0] is the root struct pointer
 					}
 
 					//Recurse on structures
-					retErr = append(retErr, processStruct(fldType, offset, structIndex, parentName+fld.Name+".")...)
+					retErr = append(retErr, processStruct(fldType, offset, structIndex, parentName+name+".", parentDBName+dbName+".", childPresenceIndex)...)
 					continue
 				}
 
+				//Apply the tag options
+				var tagErr error
+				if len(tags) != 0 {
+					fn, tagErr = tags.apply(v, fld, fldType, fn)
+					if tags.isCollapsed() {
+						sff = sffNoFlags
+					}
+					sff &^= sffDefaultConverters //The conversion function may no longer be the default
+					if tags.has("stream") {
+						sff |= sffIsRawBytes //The member references the RawBytes, so singular ScanRow functions need a copy
+					}
+					if tags.needsParent() {
+						sff |= sffNeedsParent
+					}
+				}
+
+				//Add the type’s validator
+				fn, sff = addValidator(fldType, fn, sff)
+
 				//If there is no function pointer than the type is invalid
-				if fn == nil {
-					retErr = append(retErr, fmt.Sprintf("%s%s: %s%s", parentName, fld.Name, cond(isPointer, "*", ""), fldType.String()))
+				if tagErr != nil {
+					retErr = append(retErr, fmt.Sprintf("%s%s: %s", parentName, name, tagErr.Error()))
+				} else if fn == nil {
+					retErr = append(retErr, fmt.Sprintf("%s%s: %s%s", parentName, name, cond(isPointer, "*", ""), fldType.String()))
+				}
+
+				//Copies do not get their own field
+				if copyOf := tags.copyOf(); len(copyOf) != 0 {
+					if isPointer || sff&sffIsRawBytes != 0 {
+						retErr = append(retErr, fmt.Sprintf("%s%s: gfs tag option “copyof”: Member cannot be a pointer or reference the RawBytes", parentName, name))
+					}
+					copies = append(copies, memberCopy{name, copyOf, parentOffset + fld.Offset, fn})
+					continue
 				}
 
-				//Store the member
-				ret.fields[fieldPos] = structField{parentOffset + fld.Offset, fn, parentStructIndex, parentName + fld.Name, fld.Name, isPointer, sff}
+				//Store the member. Members that consume 2 columns (datetime2col) get a second field for their time column.
+				if tags.numColumns() == 2 {
+					ret.fields[fieldPos] = structField{parentOffset + fld.Offset, fn, parentStructIndex, parentName + name + ".date", name + ".date", isPointer, sff | sffSharedMember, fldType, presenceIndex, parentDBName + dbName + ".date", dbName + ".date", nil}
+					ret.fields[fieldPos+1] = structField{parentOffset + fld.Offset, convTimeOfDay, parentStructIndex, parentName + name + ".time", name + ".time", isPointer, sff | sffSharedMember, fldType, presenceIndex, parentDBName + dbName + ".time", dbName + ".time", nil}
+					fieldPos += 2
+					continue
+				}
+				if !isPointer && sff&sffIsRawBytes == 0 {
+					copyableFields[fld.Name] = fieldPos
+				}
+				ret.fields[fieldPos] = structField{parentOffset + fld.Offset, fn, parentStructIndex, parentName + name, name, isPointer, sff, fldType, presenceIndex, parentDBName + dbName, dbName, nil}
 				fieldPos++
 			}
 
+			//Run the conversion functions of copies after the member they copy. As both are in the same structure, the copy is at a fixed distance from the member.
+			var copiedFields []int
+			for _, c := range copies {
+				fieldIndex, ok := copyableFields[c.copyOf]
+				if !ok {
+					retErr = append(retErr, fmt.Sprintf("%s%s: gfs tag option “copyof”: Member “%s” must be a non-pointer member of the same structure that consumes a single column and does not reference the RawBytes", parentName, c.name, c.copyOf))
+					continue
+				} else if c.fn == nil {
+					continue //The invalid type was already reported
+				}
+				sf := &ret.fields[fieldIndex]
+				if sf.copier == nil {
+					copiedFields = append(copiedFields, fieldIndex)
+				}
+				prevCopier, copyFn, copyOffset := sf.copier, c.fn, int(c.offset)-int(sf.offset)
+				sf.copier = func(in []byte, p upt) error {
+					if prevCopier != nil {
+						if err := prevCopier(in, p); err != nil {
+							return err
+						}
+					}
+					return copyFn(in, upt(unsafe.Add(unsafe.Pointer(p), copyOffset)))
+				}
+				sf.flags = sf.flags&^sffDefaultConverters | sffNeedsParent //The conversion function is no longer the default, and writes the copy outside of the member
+			}
+			for _, fieldIndex := range copiedFields {
+				sf := &ret.fields[fieldIndex]
+				sf.converter = withCopier(sf.converter, sf.copier)
+			}
+
 			return
 		}
-		if err := processStruct(t, 0, 0, ""); len(err) != 0 {
-			return StructModel{}, fmt.Errorf("Invalid types found for members:\n%s", strings.Join(err, "\n"))
+		if err := processStruct(t, 0, 0, "", "", 0); len(err) != 0 {
+			return StructModel{}, errors.New("Invalid types found for members:\n" + strings.Join(err, "\n"))
 		}
 	}
 
@@ -230,17 +400,31 @@ func createStructModelFromStruct(t reflect.Type) (StructModel, error) {
 
 // Convert a scalar reflect.Type to its conversion function
 func scalarToConversionFunc(fldType reflect.Type) (converterFunc, structFieldFlags) {
+	//Handle registered conversion functions
+	remLock.RLock()
+	rf := registeredConverters[fldType]
+	remLock.RUnlock()
+	if rf != nil {
+		return rf, sffNoFlags
+	}
+
+	//Handle durations (in nanoseconds, see the “duration” gfs tag option for other units)
+	if fldType == lookupType.duration {
+		return convDuration, sffIsInteger
+	}
+
 	//Handle real scalar types
 	k := fldType.Kind()
 	cf := scalarConverters[k]
 	if cf != nil {
-		return cf, sffNoFlags
+		return cf, cond(k == reflect.String, sffIsString, cond(isIntegerKind(k), sffIsInteger, sffNoFlags))
 	}
 
 	//Handle pretend scalar types
 	switch k {
 	case reflect.Slice:
-		if fldType.AssignableTo(lookupType.byteArray) {
+		//Typedef derivatives of sql.RawBytes cannot be told apart from other byte slices, so only sql.RawBytes itself references the RawBytes. All others are copied.
+		if isByteSlice(fldType) {
 			if fldType == lookupType.rawBytes {
 				return convRawBytes, sffIsRawBytes
 			} else {
@@ -249,16 +433,89 @@ func scalarToConversionFunc(fldType reflect.Type) (converterFunc, structFieldFla
 		}
 	case reflect.Struct:
 		if f := nullTypeStructConverters[fldType]; f != nil {
-			return f, sffIsNullable | cond(fldType == lookupType.nullRawBytes, sffIsRawBytes, sffNoFlags)
+			sff := sffIsNullable | cond(fldType == lookupType.nullRawBytes, sffIsRawBytes, sffNoFlags) | cond(fldType == lookupType.nullTime || fldType == lookupType.sqlNullTime, sffIsTime, sffNoFlags) | cond(fldType == lookupType.nullString, sffIsString, sffNoFlags)
+			if valFld, ok := fldType.FieldByName("Val"); ok && isIntegerKind(valFld.Type.Kind()) {
+				sff |= sffIsInteger
+			}
+			return f, sff
+		} else if f := atomicStructConverters[fldType]; f != nil {
+			return f, sffNoFlags
 		} else if fldType == lookupType.time {
-			return convTime, sffNoFlags
+			return convTime, sffIsTime
+		} else if fldType == lookupType.lazyTime {
+			return convLazyTime, sffNoFlags
+		} else if f := makeSQLNullConverter(fldType); f != nil {
+			return f, sffIsNullable | cond(fldType.Field(0).Type == lookupType.time, sffIsTime, sffNoFlags)
+		} else if f, sff := makeNullTypeConverter(fldType); f != nil {
+			return f, sff
+		}
+	case reflect.Array:
+		if f := makeFixedArrayConverter(fldType); f != nil {
+			return f, sffNoFlags
+		}
+	case reflect.Interface:
+		if fldType == lookupType.writer {
+			return convWriter, sffNoFlags
 		}
 	}
 
+	//Handle types with custom conversion interfaces (checked after the built-in types so typedefs of scalars keep their conversion)
+	if implementsDirectly(fldType, lookupType.bytesScanner) {
+		return makeBytesScannerConverter(fldType), sffNoFlags
+	} else if implementsDirectly(fldType, lookupType.bytesConsumer) {
+		return makeBytesConsumerConverter(fldType), sffNoFlags
+	}
+
+	//Handle types that the column can be written to (ex: bytes.Buffer)
+	if implementsDirectly(fldType, lookupType.writer) {
+		return makeWriterConverter(fldType), sffNoFlags
+	}
+
+	//Handle types implementing sql.Scanner (checked last as the built-in conversions are faster)
+	if implementsDirectly(fldType, lookupType.sqlScanner) {
+		return makeSQLScannerConverter(fldType), sffNoFlags
+	}
+
+	//Fall back to types implementing encoding.TextUnmarshaler (ex: netip.Addr, big.Int), and then encoding.BinaryUnmarshaler (many types implement both, and columns are more commonly text)
+	if implementsDirectly(fldType, lookupType.textUnmarshaler) {
+		return makeTextUnmarshalerConverter(fldType), sffAllocPointer
+	} else if implementsDirectly(fldType, lookupType.binaryUnmarshaler) {
+		return makeBinaryUnmarshalerConverter(fldType), sffAllocPointer
+	}
+
 	//Return no match
 	return nil, sffNoFlags
 }
 
+/*
+implementsDirectly returns if a pointer to t implements iface with methods declared on t itself.
+Methods promoted from embedded members are not counted, so structures that embed a type implementing iface (ex: struct{ time.Time; By string }) are still recursed into. A structure that both embeds such a type and declares the methods itself is also recursed into.
+*/
+func implementsDirectly(t, iface reflect.Type) bool {
+	if !reflect.PointerTo(t).Implements(iface) {
+		return false
+	} else if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if !fld.Anonymous {
+			continue
+		}
+		embedded := fld.Type
+		if k := embedded.Kind(); k != reflect.Pointer && k != reflect.Interface {
+			embedded = reflect.PointerTo(embedded)
+		}
+		for m := 0; m < iface.NumMethod(); m++ {
+			if _, ok := embedded.MethodByName(iface.Method(m).Name); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Creates a non-simple StructModel
 func getMultipleStructsAsStructModel(vars []any) (StructModel, error) {
 	//Pull the StructModels that we already have cached
@@ -341,16 +598,19 @@ func getMultipleStructsAsStructModel(vars []any) (StructModel, error) {
 
 	//Create a StructModel for return
 	pointerSize := unsafe.Sizeof((*int)(nil))
-	curPointerIndex, curFieldIndex := 0, 0
+	curPointerIndex, curFieldIndex, curPresenceIndex := 0, 0, 0
 	for smIndex, sm := range varSMs {
 		//Store the variable as a pointer
-		newSM.pointers[curPointerIndex] = structPointer{0, pointerSize * uintptr(smIndex), "Param" + strconv.Itoa(smIndex)}
+		newSM.pointers[curPointerIndex] = structPointer{0, pointerSize * uintptr(smIndex), "Param" + strconv.Itoa(smIndex), newSM.rTypes[smIndex], false}
 		curPointerIndex++
 
 		//Copy over its members
 		for fieldIndex, field := range sm.fields {
 			tempField := field
 			tempField.pointerIndex += curPointerIndex
+			if tempField.presenceIndex != 0 {
+				tempField.presenceIndex += curPresenceIndex
+			}
 			//While I could update the name field here, to include the parameter number, I feel that is a waste of processing
 			newSM.fields[curFieldIndex+fieldIndex] = tempField
 		}
@@ -363,6 +623,17 @@ func getMultipleStructsAsStructModel(vars []any) (StructModel, error) {
 			newSM.pointers[curPointerIndex+pointerIndex] = tempPointer
 		}
 		curPointerIndex += len(sm.pointers)
+
+		//Copy over its presences (their pointer indexes are adjusted the same as its members)
+		for _, presence := range sm.presences {
+			tempPresence := presence
+			tempPresence.pointerIndex += curPointerIndex - len(sm.pointers)
+			if tempPresence.parentIndex != 0 {
+				tempPresence.parentIndex += curPresenceIndex
+			}
+			newSM.presences = append(newSM.presences, tempPresence)
+		}
+		curPresenceIndex += len(sm.presences)
 	}
 
 	return newSM, nil
@@ -373,10 +644,11 @@ func createStructModelFromScalar(t reflect.Type) (StructModel, error) {
 	if convFunc == nil {
 		return StructModel{}, errors.New("Invalid scalar type")
 	}
+	convFunc, sff = addValidator(t, convFunc, sff)
 
 	sm := StructModel{
-		[]structField{{0, convFunc, 0, "Scalar-" + t.Name(), "", false, sff}},
-		nil, []reflect.Type{t}, false,
+		[]structField{{0, convFunc, 0, "Scalar-" + t.Name(), "", false, sff, t, 0, "Scalar-" + t.Name(), "", nil}},
+		nil, []reflect.Type{t}, false, nil,
 	}
 
 	//Cache the structure model
@@ -387,8 +659,36 @@ func createStructModelFromScalar(t reflect.Type) (StructModel, error) {
 	return sm, nil
 }
 
+/*
+RegisterConverter registers a conversion function for members (and scalar variables) of type t, which takes precedence over the built-in conversions (ex: type Money int64 stored as text). fn receives the column (nil for NULL) and dst, a pointer to the member (*T).
+
+Conversion functions are looked up when types are first modeled and the models are cached, so registration must happen before the first ModelStruct call that includes t. Registering a type again replaces its conversion function, and passing nil removes it.
+*/
+func RegisterConverter(t reflect.Type, fn func(in []byte, dst any) error) {
+	remLock.Lock()
+	defer remLock.Unlock()
+	if fn == nil {
+		delete(registeredConverters, t)
+		return
+	}
+	proto := reflect.New(t).Interface()
+	registeredConverters[t] = func(in []byte, p upt) error {
+		return fn(in, pointer2Interface(proto, unsafe.Pointer(p)))
+	}
+}
+
 //-------------------------------------Misc-------------------------------------
 
+// withCopier returns a conversion function that runs fn and then the copier of the field (see structField.copier)
+func withCopier(fn, copier converterFunc) converterFunc {
+	return func(in []byte, p upt) error {
+		if err := fn(in, p); err != nil {
+			return err
+		}
+		return copier(in, p)
+	}
+}
+
 // Equals returns if these are from the same structs
 func (sm StructModel) Equals(sm2 StructModel) bool {
 	if len(sm.rTypes) != len(sm2.rTypes) {
@@ -401,3 +701,149 @@ func (sm StructModel) Equals(sm2 StructModel) bool {
 	}
 	return true
 }
+
+// ExpectedColumns returns the number of columns a SELECT query must return to be scanned with this StructModel (the number of flattened members across all variables)
+func (sm StructModel) ExpectedColumns() int {
+	return len(sm.fields)
+}
+
+/*
+ToMap returns the values of the flattened members of already scanned variables, keyed by the names used to match columns (see RowReaderNamed). The variables must be pointers of the types this StructModel was created with.
+
+Pointer members are dereferenced. Members that are nil pointers, or are inside nil structure pointers, have a nil value.
+*/
+func (sm StructModel) ToMap(outPointers ...any) (map[string]any, error) {
+	//Confirm the variables
+	if len(outPointers) != len(sm.rTypes) {
+		return nil, fmt.Errorf("outPointers is incorrect length %d!=%d", len(outPointers), len(sm.rTypes))
+	}
+	for i, v := range outPointers {
+		t := reflect.TypeOf(v)
+		if t == nil || t.Kind() != reflect.Pointer || t.Elem() != sm.rTypes[i] {
+			return nil, fmt.Errorf("outPointers[%d] type is incorrect (%s)!=(*%s)", i, fmt.Sprint(t), sm.rTypes[i].String())
+		} else if reflect.ValueOf(v).IsNil() {
+			return nil, fmt.Errorf("outPointers[%d]: %s", i, ErrPointerNotInitialized.Error())
+		}
+	}
+
+	//Determine the structure pointers
+	pointers := make([]unsafe.Pointer, len(sm.pointers)+1)
+	if sm.isSimple {
+		pointers[0] = interface2Pointer(outPointers[0])
+	} else {
+		outArr := make([]unsafe.Pointer, len(outPointers))
+		for i, v := range outPointers {
+			outArr[i] = interface2Pointer(v)
+		}
+		pointers[0] = unsafe.Pointer(&outArr[0])
+	}
+	for i, p := range sm.pointers {
+		if parent := pointers[p.parentIndex]; parent != nil {
+			pointers[i+1] = *(*unsafe.Pointer)(unsafe.Add(parent, p.offset))
+		}
+	}
+
+	//Read the members
+	names, _ := sm.fieldNames()
+	ret := make(map[string]any, len(sm.fields))
+	for i, sf := range sm.fields {
+		var p unsafe.Pointer
+		if parent := pointers[sf.pointerIndex]; parent != nil {
+			if p = unsafe.Add(parent, sf.offset); sf.isPointer {
+				p = *(*unsafe.Pointer)(p)
+			}
+		}
+		if p == nil {
+			ret[names[i]] = nil
+		} else {
+			ret[names[i]] = reflect.NewAt(sf.rType, p).Elem().Interface()
+		}
+	}
+	return ret, nil
+}
+
+// FieldInfo is the metadata of a flattened member. See StructModel.FieldFlags()
+type FieldInfo struct {
+	Name       string //The name used to match the member’s column (see RowReaderNamed)
+	IsPointer  bool   //If the member is a pointer
+	IsNullable bool   //If the member is a nullable struct (nulltypes, sql.Null* or sql.Null[T])
+	IsRawBytes bool   //If the member is a RawBytes type (sql.RawBytes or nulltypes.NullRawBytes) or otherwise references the RawBytes (gfs:"stream")
+}
+
+// FieldFlags returns the metadata of the flattened members, in column order
+func (sm StructModel) FieldFlags() []FieldInfo {
+	names, _ := sm.fieldNames()
+	ret := make([]FieldInfo, len(sm.fields))
+	for i, sf := range sm.fields {
+		ret[i] = FieldInfo{names[i], sf.isPointer, sf.flags&sffIsNullable != 0, sf.flags&sffIsRawBytes != 0}
+	}
+	return ret
+}
+
+// Debug returns a human readable dump of the StructModel: its types, flattened members (with their offsets, pointer indexes, flags, and conversion functions), structure pointers, and presence members
+func (sm StructModel) Debug() string {
+	var sb strings.Builder
+	typeNames := make([]string, len(sm.rTypes))
+	for i, t := range sm.rTypes {
+		typeNames[i] = t.String()
+	}
+	_, _ = fmt.Fprintf(&sb, "StructModel(%s) isSimple=%t\n", strings.Join(typeNames, ", "), sm.isSimple)
+
+	_, _ = fmt.Fprintf(&sb, "Fields (%d):\n", len(sm.fields))
+	for i, sf := range sm.fields {
+		_, _ = fmt.Fprintf(&sb, "  [%d] %s (base=%s) type=%s offset=%d pointerIndex=%d isPointer=%t flags=%s presenceIndex=%d converter=%s\n",
+			i, sf.name, sf.baseName, fmt.Sprint(sf.rType), sf.offset, sf.pointerIndex, sf.isPointer, sf.flags.String(), sf.presenceIndex, funcName(sf.converter),
+		)
+	}
+
+	_, _ = fmt.Fprintf(&sb, "Pointers (%d, index 0 is the root):\n", len(sm.pointers))
+	for i, p := range sm.pointers {
+		_, _ = fmt.Fprintf(&sb, "  [%d] %s type=%s parentIndex=%d offset=%d nilOnNull=%t\n", i+1, p.name, p.rType.String(), p.parentIndex, p.offset, p.nilOnNull)
+	}
+
+	if len(sm.presences) != 0 {
+		_, _ = fmt.Fprintf(&sb, "Presences (%d):\n", len(sm.presences))
+		for i, p := range sm.presences {
+			_, _ = fmt.Fprintf(&sb, "  [%d] parentIndex=%d pointerIndex=%d offset=%d\n", i+1, p.parentIndex, p.pointerIndex, p.offset)
+		}
+	}
+	return sb.String()
+}
+
+// String returns the names of the set flags separated by “|”
+func (sff structFieldFlags) String() string {
+	var names []string
+	for _, f := range []struct {
+		flag structFieldFlags
+		name string
+	}{
+		{sffIsRawBytes, "RawBytes"},
+		{sffIsNullable, "Nullable"},
+		{sffIsTime, "Time"},
+		{sffIsString, "String"},
+		{sffSharedMember, "SharedMember"},
+		{sffSkipNull, "SkipNull"},
+		{sffIsInteger, "Integer"},
+		{sffAllocPointer, "AllocPointer"},
+		{sffNeedsParent, "NeedsParent"},
+	} {
+		if sff&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
+
+// funcName returns the name of the function (closures are named after the function that created them)
+func funcName(fn converterFunc) string {
+	if fn == nil {
+		return "nil"
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
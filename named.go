@@ -6,6 +6,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
 )
 
 /*
@@ -14,11 +18,15 @@ Columns names are only matched on the first row scan that this RowReaderNamed is
 Do not scan subsequent rows that contain columns in a different order.
 
 Column names must match either the full member name path with dots for nested structures, or just the name of the member. Top level scalars can be matched by “Param”+Base0Index.
-If a conflict arises due to requesting an ambiguous member name, and there is no top level member with the name, an error is returned. A field cannot also be matched to more than one column name. See TODO note in readme for more information.
+If a conflict arises due to requesting an ambiguous member name, and there is no top level member with the name, an error is returned. A field cannot also be matched to more than one column name (see RowReader.AllowDuplicateColumns). See TODO note in readme for more information.
 */
 type RowReaderNamed struct {
 	RowReader
 	hasAlreadyMatchedCols, hasError bool
+	fieldDriven                     bool     //If the members select the columns instead of the columns selecting the members (see CreateReaderNamedSubset)
+	allowDuplicates                 bool     //If duplicate columns are discarded instead of returning an error (see RowReader.AllowDuplicateColumns)
+	prefixes                        []string //The column name prefix of each parameter (see StructModel.CreateReaderNamedPrefixed)
+	unmatchedFields                 []string //The names of the members that no column matched (see RowReader.UnmatchedFields)
 }
 
 // CreateReaderNamed creates a RowReaderNamed from the StructModel
@@ -30,6 +38,62 @@ func (sm StructModel) CreateReaderNamed() *RowReader {
 	return &rr.RowReader
 }
 
+/*
+CreateReaderNamedSubset creates a RowReaderNamed from the StructModel where the members select the columns instead of the columns selecting the members.
+Each member must match exactly one column (by full name, or otherwise by base name), and all other columns are discarded. This allows scanning a subset of a query’s columns (ex: SELECT *) into a smaller structure.
+*/
+func (sm StructModel) CreateReaderNamedSubset() *RowReader {
+	rr := &RowReaderNamed{
+		RowReader:   *sm.CreateReader(),
+		fieldDriven: true,
+	}
+	rr.rrType = rrtNamed
+	return &rr.RowReader
+}
+
+/*
+CreateReaderNamedPrefixed creates a RowReaderNamed from a StructModel of multiple parameters (ex: the tables of a JOIN), where the columns of each parameter are named with its prefix (ex: “u.” and “a.” for “u.ID” and “a.ID”), given in parameter order.
+The members of each parameter are matched (by full name, or otherwise by base name) with the prefix added, so members with the same name in different parameters are not ambiguous. An empty prefix leaves a parameter’s members unprefixed. Top level scalars keep their “Param”+Base0Index name.
+The column names must include the prefixes (ex: “SELECT u.ID AS `u.ID`” for MySQL, whose column names do not include the table).
+*/
+func (sm StructModel) CreateReaderNamedPrefixed(prefixes ...string) (*RowReader, error) {
+	if len(prefixes) != len(sm.rTypes) {
+		return nil, fmt.Errorf("Number of prefixes (%d) does not match number of parameters (%d)", len(prefixes), len(sm.rTypes))
+	}
+
+	//Find the parameter of each pointer. When there are multiple parameters, they are the top level pointers (in order).
+	pointerParams := make([]int, len(sm.pointers)+1)
+	if len(sm.rTypes) > 1 {
+		nextParam := 0
+		for i, p := range sm.pointers {
+			if p.parentIndex == 0 {
+				pointerParams[i+1] = nextParam
+				nextParam++
+			} else {
+				pointerParams[i+1] = pointerParams[p.parentIndex]
+			}
+		}
+	}
+
+	//Add the prefixes to the member names
+	fields := make([]structField, len(sm.fields))
+	copy(fields, sm.fields)
+	for i, f := range fields {
+		if len(f.baseName) != 0 {
+			prefix := prefixes[pointerParams[f.pointerIndex]]
+			fields[i].name, fields[i].baseName = prefix+f.name, prefix+f.baseName
+		}
+	}
+	sm.fields = fields
+
+	rr := &RowReaderNamed{
+		RowReader: *sm.CreateReader(),
+		prefixes:  append([]string(nil), prefixes...),
+	}
+	rr.rrType = rrtNamed
+	return &rr.RowReader, nil
+}
+
 func (rrn *RowReaderNamed) initNamed(rows *sql.Rows) error {
 	//Quick exit conditions
 	if rrn.rrType != rrtNamed {
@@ -43,39 +107,128 @@ func (rrn *RowReaderNamed) initNamed(rows *sql.Rows) error {
 	}
 
 	//Get the column names
-	var colNames []string
-	if _colNames, err := rows.Columns(); err != nil {
+	colNames, err := rows.Columns()
+	if err != nil {
 		rrn.hasError, rrn.hasAlreadyMatchedCols = true, true
 		return err
-	} else if len(_colNames) != len(rrn.sm.fields) {
-		rrn.hasError, rrn.hasAlreadyMatchedCols = true, true
-		return fmt.Errorf("Number of columns in row (%d) does not match number of expected fields (%d)", len(_colNames), len(rrn.sm.fields))
-	} else {
-		colNames = _colNames
-	}
-
-	//Make a list of the base names and names (fix the names on top level scalar parameters)
-	fieldNames := make([]string, len(colNames))
-	fieldBaseNames := make([]string, len(colNames))
-	{
-		fields := rrn.sm.fields
-		for i := range fieldNames {
-			basename := fields[i].baseName
-			fieldBaseNames[i] = basename
-			if len(basename) == 0 {
-				fieldNames[i] = rrn.sm.pointers[fields[i].pointerIndex-1].name
-			} else {
-				fieldNames[i] = fields[i].name
-			}
+	}
+	return rrn.matchColumns(colNames)
+}
+
+// matchColumns matches the column names against the fields and reorganizes the fields into column order
+func (rrn *RowReaderNamed) matchColumns(colNames []string) error {
+	//Get the field index for each column (from the shared cache if possible)
+	var cacheKey string
+	var colIndexToFieldIndex []int
+	if CacheNamedMatches {
+		cacheKey = rrn.namedMatchKey(colNames)
+		if cached, ok := namedMatchCache.Load(cacheKey); ok {
+			colIndexToFieldIndex = cached.([]int)
+		}
+	}
+	if colIndexToFieldIndex == nil {
+		var err error
+		if rrn.fieldDriven {
+			colIndexToFieldIndex, err = rrn.sm.matchFieldsToColumns(colNames, rrn.allowDuplicates)
+		} else {
+			colIndexToFieldIndex, err = rrn.sm.matchColumnsToFields(colNames, rrn.allowDuplicates)
+		}
+		if err != nil {
+			rrn.hasError, rrn.hasAlreadyMatchedCols = true, true
+			return err
+		}
+		if CacheNamedMatches {
+			namedMatchCache.Store(cacheKey, colIndexToFieldIndex)
+		}
+	}
+	rrn.hasAlreadyMatchedCols = true
+
+	//Record the members that no column matched
+	fieldMatched := make([]bool, len(rrn.sm.fields))
+	for _, fieldIndex := range colIndexToFieldIndex {
+		if fieldIndex != -1 {
+			fieldMatched[fieldIndex] = true
+		}
+	}
+	fieldNames, _ := rrn.sm.fieldNames()
+	rrn.unmatchedFields = []string{}
+	for fieldIndex, matched := range fieldMatched {
+		if !matched {
+			rrn.unmatchedFields = append(rrn.unmatchedFields, fieldNames[fieldIndex])
 		}
 	}
 
+	//Reorganize the fields in the RowReader
+	newFieldsList := rrn.sm.fieldsInColumnOrder(colIndexToFieldIndex)
+	rrn.sm.fields = newFieldsList
+
+	//Resize the raw bytes buffers to the number of columns
+	if len(newFieldsList) != len(rrn.rawBytesArr) {
+		rrn.rawBytesArr = make([]sql.RawBytes, len(newFieldsList))
+		rrn.rawBytesAny = make([]any, len(newFieldsList))
+		for i := range rrn.rawBytesArr {
+			rrn.rawBytesAny[i] = &rrn.rawBytesArr[i]
+		}
+	}
+
+	return nil
+}
+
+// CacheNamedMatches shares the column matching of RowReaderNamed (the field index for each column) across all readers with the same StructModel types and column names, so only the first reader to see a query’s columns does the matching. Defaults to false.
+// Every distinct list of column names is cached for the life of the program, so this should not be used with dynamically generated column names.
+// Readers only check it when matching their columns, so toggling it does not affect readers that have already matched. It is read without synchronization, so set it at startup.
+var CacheNamedMatches = false
+
+// namedMatchCache holds the shared column matches (map[string][]int, keyed by RowReaderNamed.namedMatchKey). The cached slices are read-only.
+var namedMatchCache sync.Map
+
+// namedMatchKey returns the key for namedMatchCache, which identifies the types of the StructModel, the column names, the matching modes, and the prefixes
+func (rrn *RowReaderNamed) namedMatchKey(colNames []string) string {
+	var sb strings.Builder
+	sb.WriteString(cond(rrn.fieldDriven, "F", "C"))
+	sb.WriteString(cond(rrn.allowDuplicates, "D", "U"))
+	for _, t := range rrn.sm.rTypes {
+		sb.WriteString(strconv.FormatUint(uint64(uintptr(interface2Pointer(t))), 16))
+		sb.WriteByte(',')
+	}
+	for _, prefix := range rrn.prefixes {
+		sb.WriteByte(1)
+		sb.WriteString(prefix)
+	}
+	for _, colName := range colNames {
+		sb.WriteByte(0)
+		sb.WriteString(colName)
+	}
+	return sb.String()
+}
+
+/*
+matchColumnsToFields returns the field index for each column, where each column must match exactly one field.
+If allowDuplicates is true, a column is instead discarded (field index -1) if its name was already used by a previous column, or it only matches fields that were already used. Every field must still be matched.
+*/
+func (sm StructModel) matchColumnsToFields(colNames []string, allowDuplicates bool) ([]int, error) {
+	if len(colNames) != len(sm.fields) && !(allowDuplicates && len(colNames) > len(sm.fields)) {
+		return nil, columnCountError(len(colNames), len(sm.fields))
+	}
+
+	//Make a list of the base names and names
+	fieldNames, fieldBaseNames := sm.fieldNames()
+
 	//Match the columns with the RowReader members
 	//TODO: This process could be greatly enhanced, but this takes care of the base use cases
 	fieldAlreadyUsed := make([]bool, len(fieldNames))
-	colIndexToFieldIndex := make([]int, len(fieldNames))
+	colIndexToFieldIndex := make([]int, len(colNames))
+	colNameAlreadyUsed := make(map[string]struct{}, len(colNames))
 nextCol:
 	for colIndex, colName := range colNames {
+		if allowDuplicates {
+			if _, ok := colNameAlreadyUsed[colName]; ok {
+				colIndexToFieldIndex[colIndex] = -1
+				continue
+			}
+			colNameAlreadyUsed[colName] = struct{}{}
+		}
+
 		partialMatchFieldIndex, numPartialMatches := -1, 0
 		for fieldIndex, fieldName := range fieldNames {
 			if fieldAlreadyUsed[fieldIndex] {
@@ -91,26 +244,289 @@ nextCol:
 				numPartialMatches++
 			}
 		}
+		if numPartialMatches == 0 && allowDuplicates && matchesUsedField(colName, fieldNames, fieldBaseNames, fieldAlreadyUsed) {
+			colIndexToFieldIndex[colIndex] = -1
+			continue
+		}
 		if numPartialMatches != 1 {
-			rrn.hasError, rrn.hasAlreadyMatchedCols = true, true
-			return fmt.Errorf("%d matches found for column “%s”", numPartialMatches, colName)
+			return nil, fmt.Errorf("%d matches found for column “%s”", numPartialMatches, colName)
 		}
 		fieldAlreadyUsed[partialMatchFieldIndex] = true
 		colIndexToFieldIndex[colIndex] = partialMatchFieldIndex
 	}
 
-	//Reorganize the fields in the RowReader
-	rrn.hasAlreadyMatchedCols = true
-	oldFieldsList := rrn.sm.fields
-	newFieldsList := make([]structField, len(oldFieldsList))
+	//When duplicates are discarded, the column count no longer guarantees every field was matched
+	if allowDuplicates {
+		for fieldIndex, used := range fieldAlreadyUsed {
+			if !used {
+				return nil, fmt.Errorf("0 matches found for member “%s”", fieldNames[fieldIndex])
+			}
+		}
+	}
+
+	return colIndexToFieldIndex, nil
+}
+
+// matchesUsedField returns if the column name matches (by full name or base name) a field that was already used
+func matchesUsedField(colName string, fieldNames, fieldBaseNames []string, fieldAlreadyUsed []bool) bool {
+	for fieldIndex, used := range fieldAlreadyUsed {
+		if used && (fieldNames[fieldIndex] == colName || fieldBaseNames[fieldIndex] == colName) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+matchFieldsToColumns returns the field index for each column (or -1 if the column is discarded), where each field must match exactly one column.
+Fields are first matched by their full names, and then the remaining fields by their base names against the columns that were not matched by full name.
+If allowDuplicates is true, a field that matches more than one column uses the first one.
+*/
+func (sm StructModel) matchFieldsToColumns(colNames []string, allowDuplicates bool) ([]int, error) {
+	fieldNames, fieldBaseNames := sm.fieldNames()
+	colIndexToFieldIndex := make([]int, len(colNames))
+	for i := range colIndexToFieldIndex {
+		colIndexToFieldIndex[i] = -1
+	}
+
+	//Find the matching column for each field. The first pass is by full name and the second by base name.
+	fieldMatched := make([]bool, len(fieldNames))
+	for pass, names := range [][]string{fieldNames, fieldBaseNames} {
+		for fieldIndex, fieldName := range names {
+			if fieldMatched[fieldIndex] {
+				continue
+			}
+
+			matchColIndex, numMatches := -1, 0
+			for colIndex, colName := range colNames {
+				if colName == fieldName && len(fieldName) != 0 && (pass == 0 || colIndexToFieldIndex[colIndex] == -1) {
+					if numMatches == 0 || !allowDuplicates {
+						matchColIndex = colIndex
+					}
+					numMatches++
+				}
+			}
+			if numMatches > 1 && allowDuplicates {
+				numMatches = 1
+			}
+			if numMatches > 1 || (numMatches == 0 && pass == 1) {
+				return nil, fmt.Errorf("%d matches found for member “%s”", numMatches, fieldNames[fieldIndex])
+			} else if numMatches == 0 {
+				continue
+			} else if colIndexToFieldIndex[matchColIndex] != -1 {
+				return nil, fmt.Errorf("Column “%s” matches multiple members", colNames[matchColIndex])
+			}
+			colIndexToFieldIndex[matchColIndex] = fieldIndex
+			fieldMatched[fieldIndex] = true
+		}
+	}
+
+	return colIndexToFieldIndex, nil
+}
+
+// fieldsInColumnOrder returns the fields reorganized into column order. Discarded columns (field index -1) get a field that does nothing.
+func (sm StructModel) fieldsInColumnOrder(colIndexToFieldIndex []int) []structField {
+	newFieldsList := make([]structField, len(colIndexToFieldIndex))
 	for colIndex, fieldIndex := range colIndexToFieldIndex {
-		newFieldsList[colIndex] = oldFieldsList[fieldIndex]
+		if fieldIndex == -1 {
+			newFieldsList[colIndex] = discardField
+		} else {
+			newFieldsList[colIndex] = sm.fields[fieldIndex]
+		}
+	}
+	return newFieldsList
+}
+
+/*
+CreateReaderForPositions creates an index based RowReader from the StructModel where the column order is given by positions: positions[i] is the flattened member index (see StructModel.FieldFlags) that the i-th column is scanned into, or -1 to discard the column.
+This is the positional equivalent of RowReaderNamed (ex: for a column order read from a CSV header at runtime) without the column name matching. Every member must be given exactly one column.
+*/
+func (sm StructModel) CreateReaderForPositions(positions []int) (*RowReader, error) {
+	fieldUsed := make([]bool, len(sm.fields))
+	for colIndex, fieldIndex := range positions {
+		if fieldIndex == -1 {
+			continue
+		} else if fieldIndex < 0 || fieldIndex >= len(sm.fields) {
+			return nil, fmt.Errorf("Position for column #%d is out of range (%d)", colIndex, fieldIndex)
+		} else if fieldUsed[fieldIndex] {
+			return nil, fmt.Errorf("Member “%s” is given more than one column", sm.fields[fieldIndex].name)
+		}
+		fieldUsed[fieldIndex] = true
+	}
+	for fieldIndex, used := range fieldUsed {
+		if !used {
+			return nil, fmt.Errorf("Member “%s” is not given a column", sm.fields[fieldIndex].name)
+		}
 	}
-	rrn.sm.fields = newFieldsList
 
+	sm.fields = sm.fieldsInColumnOrder(positions)
+	return sm.CreateReader(), nil
+}
+
+/*
+ReaderForColumnOrder creates an index based RowReader from the StructModel for queries whose column names are known ahead of time (ex: from a query builder). The columns are matched against the members by name once, with the same rules as RowReaderNamed, so scans skip the named reader overhead.
+The columns of every query scanned with the reader must then be in this order.
+*/
+func (sm StructModel) ReaderForColumnOrder(colNames []string) (*RowReader, error) {
+	colIndexToFieldIndex, err := sm.matchColumnsToFields(colNames, false)
+	if err != nil {
+		return nil, err
+	}
+	sm.fields = sm.fieldsInColumnOrder(colIndexToFieldIndex)
+	return sm.CreateReader(), nil
+}
+
+// discardField is used for columns that are not scanned into any member
+var discardField = structField{converter: func([]byte, upt) error { return nil }}
+
+/*
+MatchColumns matches the column names against the members of a RowReaderNamed ahead of time, so the first scan skips the rows.Columns() call and the name matching.
+The columns of every query scanned with the reader must then be in this order. An error is returned if the reader is not a RowReaderNamed or its columns were already matched.
+*/
+func (rr *RowReader) MatchColumns(colNames []string) error {
+	if rr.rrType != rrtNamed {
+		return errors.New("Not a RowReaderNamed")
+	}
+	rrn := (*RowReaderNamed)(unsafe.Pointer(rr))
+	if rrn.hasAlreadyMatchedCols {
+		return errors.New("RowReaderNamed has already matched its columns")
+	}
+	return rrn.matchColumns(colNames)
+}
+
+/*
+AllowDuplicateColumns sets if a RowReaderNamed discards duplicate columns (ex: the same column selected under 2 aliases) instead of returning an error. The first matching column is scanned into the member and the duplicates are discarded.
+In the default mode a column is a duplicate if its name was already used by a previous column, or it only matches members that were already matched. For CreateReaderNamedSubset, a member that matches more than one column uses the first one.
+An error is returned if the reader is not a RowReaderNamed or its columns were already matched.
+*/
+func (rr *RowReader) AllowDuplicateColumns(on bool) error {
+	if rr.rrType != rrtNamed {
+		return errors.New("Not a RowReaderNamed")
+	}
+	rrn := (*RowReaderNamed)(unsafe.Pointer(rr))
+	if rrn.hasAlreadyMatchedCols {
+		return errors.New("RowReaderNamed has already matched its columns")
+	}
+	rrn.allowDuplicates = on
 	return nil
 }
 
+/*
+UnmatchedFields returns the names of the members of a RowReaderNamed that no column matched (ex: to warn that they were left as is), once its columns have been successfully matched (on its first scan or via MatchColumns). Otherwise nil is returned.
+The current matching modes require every member to be matched, so the list is empty unless a mode allows members without a column.
+*/
+func (rr *RowReader) UnmatchedFields() []string {
+	if rr.rrType != rrtNamed {
+		return nil
+	}
+	rrn := (*RowReaderNamed)(unsafe.Pointer(rr))
+	if !rrn.hasAlreadyMatchedCols || rrn.hasError {
+		return nil
+	}
+	return rrn.unmatchedFields
+}
+
+/*
+Freeze returns an index based RowReader whose members are in the column order matched by this RowReaderNamed (on its first scan or via MatchColumns).
+It can be used for any subsequent queries with identical columns, skipping the column matching and the named reader overhead. The frozen reader keeps this reader’s settings (ex: RowReader.OnField). An error is returned if the reader is not a RowReaderNamed or its columns have not been successfully matched.
+*/
+func (rr *RowReader) Freeze() (*RowReader, error) {
+	if rr.rrType != rrtNamed {
+		return nil, errors.New("Not a RowReaderNamed")
+	}
+	rrn := (*RowReaderNamed)(unsafe.Pointer(rr))
+	if !rrn.hasAlreadyMatchedCols || rrn.hasError {
+		return nil, errors.New("RowReaderNamed has not successfully matched its columns")
+	}
+	frozen := rrn.sm.CreateReader()
+	frozen.copySettings(rr)
+	return frozen, nil
+}
+
+// fieldNames returns the full names and base names of the fields, which are matched against column names. Top level scalar parameters are named via their pointer (“Param”+Base0Index).
+func (sm StructModel) fieldNames() (names, baseNames []string) {
+	names = make([]string, len(sm.fields))
+	baseNames = make([]string, len(sm.fields))
+	for i, f := range sm.fields {
+		baseNames[i] = f.baseName
+		if len(f.baseName) == 0 {
+			names[i] = sm.pointers[f.pointerIndex-1].name
+		} else {
+			names[i] = f.name
+		}
+	}
+	return
+}
+
+// columnsInOrder returns if the column names match the fields (by full name or base name) in the exact order of the model
+func (sm StructModel) columnsInOrder(colNames []string) bool {
+	if len(colNames) != len(sm.fields) {
+		return false
+	}
+	names, baseNames := sm.fieldNames()
+	for i, colName := range colNames {
+		if colName != names[i] && colName != baseNames[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+CreateScanner models the variables (see ModelStruct) and returns the appropriate RowReader for them:
+  - If fixedColumnOrder is true, the query’s columns must be in the same order as the flattened members, and the (faster) index based RowReader is returned.
+  - If fixedColumnOrder is false, a RowReaderNamed is returned, which matches the columns by name on its first scan. This costs a rows.Columns() call and the name matching on the first scan, and a little extra overhead on every scan.
+*/
+func CreateScanner(fixedColumnOrder bool, s ...any) (*RowReader, error) {
+	sm, err := ModelStruct(s...)
+	if err != nil {
+		return nil, err
+	} else if fixedColumnOrder {
+		return sm.CreateReader(), nil
+	}
+	return sm.CreateReaderNamed(), nil
+}
+
+// CreateScannerForColumns is the same as CreateScanner, except the column order is detected from the query’s column names. If colNames match the flattened members (by full or base name) in order, the index based RowReader is returned, otherwise a RowReaderNamed is returned.
+func CreateScannerForColumns(colNames []string, s ...any) (*RowReader, error) {
+	sm, err := ModelStruct(s...)
+	if err != nil {
+		return nil, err
+	}
+	return cond(sm.columnsInOrder(colNames), sm.CreateReader, sm.CreateReaderNamed)(), nil
+}
+
+/*
+TypedNamedModel scans rows into a T by column name, returning the values directly. It holds a RowReaderNamed, so the same rules apply: the columns are matched on the first scan, and it is NOT concurrency safe.
+Each scan starts with a zeroed T (see RowReader.ScanAllRows).
+*/
+type TypedNamedModel[T any] struct {
+	rr *RowReader
+}
+
+// NewTypedNamedModel creates a TypedNamedModel for T
+func NewTypedNamedModel[T any]() (*TypedNamedModel[T], error) {
+	sm, err := ModelStruct((*T)(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &TypedNamedModel[T]{sm.CreateReaderNamed()}, nil
+}
+
+// ScanRow scans a single row into a T. rows.Next() is called before the scan and rows.Close() is always called before returning. See RowReader.ScanRow
+func (tm *TypedNamedModel[T]) ScanRow(rows *sql.Rows) (T, error) {
+	var v T
+	err := tm.rr.DoScan(rows, []any{&v}, nil, false, true)
+	return v, err
+}
+
+// ScanRows scans the current row into a T. See RowReader.ScanRows
+func (tm *TypedNamedModel[T]) ScanRows(rows *sql.Rows) (T, error) {
+	var v T
+	err := tm.rr.DoScan(rows, []any{&v}, nil, false, false)
+	return v, err
+}
+
 /*
 ScanRowNamed does an sql.Rows.Scan into the outPointers variables for a single row using column names. Output variables must be pointers.
 
@@ -0,0 +1,64 @@
+package gofastersql
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestCacheNamedMatches(t *testing.T) {
+	defer func(on bool) { CacheNamedMatches = on }(CacheNamedMatches)
+	type row struct {
+		A int
+		B string
+	}
+	sm, err := ModelStruct(row{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newReader := func() *RowReaderNamed { return (*RowReaderNamed)(unsafe.Pointer(sm.CreateReaderNamed())) }
+	fieldOrder := func(rrn *RowReaderNamed) (ret string) {
+		for _, f := range rrn.sm.fields {
+			ret += f.name
+		}
+		return
+	}
+
+	//The first reader stores its match
+	CacheNamedMatches = true
+	rr1 := newReader()
+	if err := rr1.MatchColumns([]string{"B", "A"}); err != nil {
+		t.Fatal(err)
+	}
+	if cached, ok := namedMatchCache.Load(rr1.namedMatchKey([]string{"B", "A"})); !ok {
+		t.Fatal("Match was not cached")
+	} else if m := cached.([]int); len(m) != 2 || m[0] != 1 || m[1] != 0 {
+		t.Fatalf("Cached match is incorrect: %v", m)
+	}
+
+	//Another reader with the same columns uses the cached match instead of matching (proven with a planted reversed match)
+	poisonCols := []string{"A", "B"}
+	namedMatchCache.Store(newReader().namedMatchKey(poisonCols), []int{1, 0})
+	rr2 := newReader()
+	if err := rr2.MatchColumns(poisonCols); err != nil {
+		t.Fatal(err)
+	} else if order := fieldOrder(rr2); order != "BA" {
+		t.Fatalf("Second reader did not use the cached match: %s", order)
+	}
+
+	//Other column names and matching modes do not share the entry
+	rr3 := (*RowReaderNamed)(unsafe.Pointer(sm.CreateReaderNamedSubset()))
+	if err := rr3.MatchColumns(poisonCols); err != nil {
+		t.Fatal(err)
+	} else if order := fieldOrder(rr3); order != "AB" {
+		t.Fatalf("Subset reader used the wrong cached match: %s", order)
+	}
+
+	//The cache is ignored when turned off
+	CacheNamedMatches = false
+	rr4 := newReader()
+	if err := rr4.MatchColumns(poisonCols); err != nil {
+		t.Fatal(err)
+	} else if order := fieldOrder(rr4); order != "AB" {
+		t.Fatalf("Reader used the cache while it was off: %s", order)
+	}
+}
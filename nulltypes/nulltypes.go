@@ -1,9 +1,13 @@
-// Package nulltypes contains all the scalar types as nullable
+// Package nulltypes contains all the scalar types as nullable, and the generic Null[T] for any other type
+//
+// Each type has Ptr() and ValueOr() helpers to read its value without branching on IsNull.
 package nulltypes
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 	"unsafe"
 )
@@ -73,6 +77,13 @@ type NullTime struct {
 	Val time.Time
 }
 
+// Null makes any type that gofastersql can convert into nullable (ex: Null[MyEnum]). The concrete Null* types above remain for backward compatibility.
+// Its JSON is the JSON of T, except that byte slices (ex: Null[[]byte]) are strings instead of base64, the same as NullByteArray.
+type Null[T any] struct {
+	NullInherit
+	Val T
+}
+
 func (t NullUint8) String() string     { return getStr(t.IsNull, t.Val) }
 func (t NullUint16) String() string    { return getStr(t.IsNull, t.Val) }
 func (t NullUint32) String() string    { return getStr(t.IsNull, t.Val) }
@@ -87,7 +98,10 @@ func (t NullBool) String() string      { return getStr(t.IsNull, t.Val) }
 func (t NullString) String() string    { return getStr(t.IsNull, t.Val) }
 func (t NullByteArray) String() string { return getStr(t.IsNull, b2s(t.Val)) }
 func (t NullRawBytes) String() string  { return getStr(t.IsNull, b2s(t.Val)) }
-func (t NullTime) String() string      { return getStr(t.IsNull, t.Val.Format(`2006-01-02 15:04:05.99999`)) }
+func (t NullTime) String() string      { return getStr(t.IsNull, t.Val.Format(nullTimeStrFmt)) }
+func (t Null[T]) String() string       { return getStr(t.IsNull, t.Val) }
+
+const nullTimeStrFmt = `2006-01-02 15:04:05.999999999`
 
 func getStr[T any](isNull bool, val T) string {
 	if isNull {
@@ -97,7 +111,56 @@ func getStr[T any](isNull bool, val T) string {
 	}
 }
 
-const nullTimeFmt = `2006-01-02T15:04:05.000Z`
+// Ptr returns nil if NULL, or otherwise a pointer to a copy of Val
+func (t NullUint8) Ptr() *uint8           { return getPtr(t.IsNull, t.Val) }
+func (t NullUint16) Ptr() *uint16         { return getPtr(t.IsNull, t.Val) }
+func (t NullUint32) Ptr() *uint32         { return getPtr(t.IsNull, t.Val) }
+func (t NullUint64) Ptr() *uint64         { return getPtr(t.IsNull, t.Val) }
+func (t NullInt8) Ptr() *int8             { return getPtr(t.IsNull, t.Val) }
+func (t NullInt16) Ptr() *int16           { return getPtr(t.IsNull, t.Val) }
+func (t NullInt32) Ptr() *int32           { return getPtr(t.IsNull, t.Val) }
+func (t NullInt64) Ptr() *int64           { return getPtr(t.IsNull, t.Val) }
+func (t NullFloat32) Ptr() *float32       { return getPtr(t.IsNull, t.Val) }
+func (t NullFloat64) Ptr() *float64       { return getPtr(t.IsNull, t.Val) }
+func (t NullBool) Ptr() *bool             { return getPtr(t.IsNull, t.Val) }
+func (t NullString) Ptr() *string         { return getPtr(t.IsNull, t.Val) }
+func (t NullByteArray) Ptr() *[]byte      { return getPtr(t.IsNull, t.Val) }
+func (t NullRawBytes) Ptr() *sql.RawBytes { return getPtr(t.IsNull, t.Val) }
+func (t NullTime) Ptr() *time.Time        { return getPtr(t.IsNull, t.Val) }
+func (t Null[T]) Ptr() *T                 { return getPtr(t.IsNull, t.Val) }
+
+// ValueOr returns def if NULL, or otherwise Val
+func (t NullUint8) ValueOr(def uint8) uint8                  { return valueOr(t.IsNull, t.Val, def) }
+func (t NullUint16) ValueOr(def uint16) uint16               { return valueOr(t.IsNull, t.Val, def) }
+func (t NullUint32) ValueOr(def uint32) uint32               { return valueOr(t.IsNull, t.Val, def) }
+func (t NullUint64) ValueOr(def uint64) uint64               { return valueOr(t.IsNull, t.Val, def) }
+func (t NullInt8) ValueOr(def int8) int8                     { return valueOr(t.IsNull, t.Val, def) }
+func (t NullInt16) ValueOr(def int16) int16                  { return valueOr(t.IsNull, t.Val, def) }
+func (t NullInt32) ValueOr(def int32) int32                  { return valueOr(t.IsNull, t.Val, def) }
+func (t NullInt64) ValueOr(def int64) int64                  { return valueOr(t.IsNull, t.Val, def) }
+func (t NullFloat32) ValueOr(def float32) float32            { return valueOr(t.IsNull, t.Val, def) }
+func (t NullFloat64) ValueOr(def float64) float64            { return valueOr(t.IsNull, t.Val, def) }
+func (t NullBool) ValueOr(def bool) bool                     { return valueOr(t.IsNull, t.Val, def) }
+func (t NullString) ValueOr(def string) string               { return valueOr(t.IsNull, t.Val, def) }
+func (t NullByteArray) ValueOr(def []byte) []byte            { return valueOr(t.IsNull, t.Val, def) }
+func (t NullRawBytes) ValueOr(def sql.RawBytes) sql.RawBytes { return valueOr(t.IsNull, t.Val, def) }
+func (t NullTime) ValueOr(def time.Time) time.Time           { return valueOr(t.IsNull, t.Val, def) }
+func (t Null[T]) ValueOr(def T) T                            { return valueOr(t.IsNull, t.Val, def) }
+
+func getPtr[T any](isNull bool, val T) *T {
+	if isNull {
+		return nil
+	}
+	return &val
+}
+func valueOr[T any](isNull bool, val, def T) T {
+	if isNull {
+		return def
+	}
+	return val
+}
+
+const nullTimeFmt = time.RFC3339Nano
 
 func (t NullUint8) MarshalJSON() ([]byte, error)     { return makeJS(t.IsNull, t.Val) }
 func (t NullUint16) MarshalJSON() ([]byte, error)    { return makeJS(t.IsNull, t.Val) }
@@ -114,6 +177,14 @@ func (t NullString) MarshalJSON() ([]byte, error)    { return qtMakeJS(t.IsNull,
 func (t NullByteArray) MarshalJSON() ([]byte, error) { return qtMakeJS(t.IsNull, b2s(t.Val)) }
 func (t NullRawBytes) MarshalJSON() ([]byte, error)  { return qtMakeJS(t.IsNull, b2s(t.Val)) }
 func (t NullTime) MarshalJSON() ([]byte, error)      { return qtMakeJS(t.IsNull, t.Val.Format(nullTimeFmt)) }
+func (t Null[T]) MarshalJSON() ([]byte, error) {
+	if b, ok := asByteSlice(&t.Val); ok {
+		return qtMakeJS(t.IsNull, b2s(*b))
+	} else if t.IsNull {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Val)
+}
 
 func makeJS[T any](isNull bool, val T) ([]byte, error) {
 	if isNull {
@@ -130,6 +201,81 @@ func qtMakeJS(isNull bool, val string) ([]byte, error) {
 	}
 }
 
+func (t *NullUint8) UnmarshalJSON(b []byte) error     { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullUint16) UnmarshalJSON(b []byte) error    { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullUint32) UnmarshalJSON(b []byte) error    { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullUint64) UnmarshalJSON(b []byte) error    { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullInt8) UnmarshalJSON(b []byte) error      { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullInt16) UnmarshalJSON(b []byte) error     { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullInt32) UnmarshalJSON(b []byte) error     { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullInt64) UnmarshalJSON(b []byte) error     { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullFloat32) UnmarshalJSON(b []byte) error   { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullFloat64) UnmarshalJSON(b []byte) error   { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullBool) UnmarshalJSON(b []byte) error      { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullString) UnmarshalJSON(b []byte) error    { return unJS(b, &t.IsNull, &t.Val) }
+func (t *NullByteArray) UnmarshalJSON(b []byte) error { return unJSBytes(b, &t.IsNull, &t.Val) }
+func (t *NullRawBytes) UnmarshalJSON(b []byte) error  { return unJSBytes(b, &t.IsNull, &t.Val) }
+
+// unJS sets isNull for a JSON null (and zeroes val), or otherwise unmarshals the JSON into val
+func unJS[T any](b []byte, isNull *bool, val *T) error {
+	var v T
+	if string(b) == "null" {
+		*isNull, *val = true, v
+		return nil
+	}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*isNull, *val = false, v
+	return nil
+}
+
+// unJSBytes is unJS for byte slices, which are marshaled as strings instead of base64
+func unJSBytes[T ~[]byte](b []byte, isNull *bool, val *T) error {
+	var s string
+	if err := unJS(b, isNull, &s); err != nil {
+		return err
+	} else if *isNull {
+		*val = nil
+	} else {
+		*val = T(s)
+	}
+	return nil
+}
+
+func (t *NullTime) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		t.IsNull, t.Val = true, time.Time{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	} else if v, err := time.Parse(nullTimeFmt, s); err != nil {
+		return err
+	} else {
+		t.IsNull, t.Val = false, v
+	}
+	return nil
+}
+
+func (t *Null[T]) UnmarshalJSON(b []byte) error {
+	if bytes, ok := asByteSlice(&t.Val); ok {
+		return unJSBytes(b, &t.IsNull, bytes)
+	}
+	return unJS(b, &t.IsNull, &t.Val)
+}
+
+// asByteSlice returns the value as a *[]byte if T is a byte slice type (ex: []byte or sql.RawBytes), which Null[T] marshals like NullByteArray
+func asByteSlice[T any](v *T) (*[]byte, bool) {
+	if t := reflect.TypeOf(v).Elem(); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	return (*[]byte)(unsafe.Pointer(v)), true
+}
+
 // b2s (Unsafe!) converts a byte slice to a string
 func b2s(b []byte) string {
 	return *(*string)(unsafe.Pointer(&b))
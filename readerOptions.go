@@ -0,0 +1,251 @@
+//Per reader options that modify how members are converted
+
+package gofastersql
+
+import (
+	"fmt"
+	nt "github.com/dakusan/gofastersql/nulltypes"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// ReaderOptions modifies how the members of a RowReader are converted. See StructModel.CreateReaderWithOptions()
+type ReaderOptions struct {
+	//Layouts (see time.Parse) that are tried, in order, before timestamps and the default layout when converting text into time.Time and nullable time (nulltypes.NullTime, sql.NullTime and both Null[time.Time]) members.
+	//Members with gfs tag options are not affected, including datetime2col members (whose DATE column is always parsed in UTC). LazyTime members keep their raw text, so pass the options to LazyTime.TimeWith() instead.
+	TimeLayouts []string
+
+	//The location that text times without a time zone are parsed in, and that timestamps are converted to. Defaults to UTC. This affects the same members as TimeLayouts.
+	TimeLocation *time.Location
+
+	//Members whose column is NULL are set to their zero value before conversion, so no values are kept from the prior row (ex: []byte members otherwise keep their prior value on NULL).
+	//Interface members, members that the column is written into (io.Writer), and members converted from 2 columns (datetime2col) are not affected.
+	ZeroNullMembers bool
+
+	//String and nulltypes.NullString members share the strings of repeated values (up to MaxInternedStrings unique values per reader), which reduces allocations for low cardinality columns (ex: status codes). Members with gfs tag options are not affected.
+	InternStrings bool
+
+	//The base (see strconv.ParseInt) that integer and nulltypes integer members are parsed in (ex: 16 for hexadecimal columns). 0 uses the default of 10.
+	//Members with gfs tag options or a registered Validator are not affected, as their conversion functions are built in base 10 when the model is created. Give those members a gfs:"base:N" tag as their first option instead (ex: gfs:"base:16,onoverflow:clamp"), which always takes precedence over this.
+	DefaultIntBase int
+
+	//Conversion functions are not called for NULL columns, so their members are left as is (ex: the zero values of a new structure) instead of being set to their NULL value. This is a micro-optimization for sparse tables with many NULL columns.
+	//Callbacks set via RowReader.OnField() are also not called for these members. Members affected by ZeroNullMembers are still zeroed.
+	SkipNullConversions bool
+
+	//Nullable members (nulltypes, sql.Null* and sql.Null[T]) are never set to NULL. NULL columns instead set them to their zero value while marked as not NULL (ex: IsNull=false with Val=""), for consumers that do not understand NULL.
+	//This takes precedence over SkipNullConversions for these members. nulltypes.NullRawBytes members scanned with the singular ScanRow functions are not affected (see RowReader.ScanRow).
+	CoalesceNulls bool
+
+	//A copy of all the columns is kept after each scan (see RowReader.LastRawRow()), so the original row can be relayed without a second scan. This does not modify conversions.
+	KeepRawRow bool
+
+	//Members are named by their db tags (ex: db:"full_name") instead of their Go names, for matching columns with a RowReaderNamed (ex: to scan into anonymous structures declared inline) and for the names in errors and RowReader.OnField().
+	//Members without a db tag name keep their Go names, and the names of nested members use the db tag names of their structures too (ex: “addr.zip”). Off by default, as the db tags of existing structures (ex: from sqlx) would otherwise change how they are matched.
+	DBTagNames bool
+}
+
+// MaxInternedStrings is the maximum number of unique strings a RowReader keeps for ReaderOptions.InternStrings. Values past this are still converted, but are not interned.
+const MaxInternedStrings = 1024
+
+// CreateReaderWithOptions creates a RowReader from the StructModel whose conversions are modified by the options
+func (sm StructModel) CreateReaderWithOptions(opts ReaderOptions) *RowReader {
+	rr := sm.withOptions(opts).CreateReader()
+	rr.keepRawRow = opts.KeepRawRow
+	return rr
+}
+
+// CreateReaderNamedWithOptions creates a RowReaderNamed from the StructModel whose conversions are modified by the options
+func (sm StructModel) CreateReaderNamedWithOptions(opts ReaderOptions) *RowReader {
+	rr := sm.withOptions(opts).CreateReaderNamed()
+	rr.keepRawRow = opts.KeepRawRow
+	return rr
+}
+
+/*
+CreateReaderWithConverters creates a RowReader from the StructModel where the conversion functions of the members are replaced by the ones in converters, keyed by the names used to match columns (see RowReaderNamed). The cached StructModel is not modified.
+
+Each conversion function receives the column (nil for NULL) and a pointer to the member (or the allocated value for pointer members). An error is returned if a name does not match a member.
+Members that are copies of a replaced member’s column (gfs:"copyof") are still converted with their own conversion functions after it.
+RawBytes members scanned with the singular ScanRow functions still have their column copied via the default conversion function (see RowReader.ScanRow).
+*/
+func (sm StructModel) CreateReaderWithConverters(converters map[string]func([]byte, unsafe.Pointer) error) (*RowReader, error) {
+	fields := make([]structField, len(sm.fields))
+	copy(fields, sm.fields)
+	sm.fields = fields
+
+	names, _ := sm.fieldNames()
+	nameToIndex := make(map[string]int, len(names))
+	for i, name := range names {
+		nameToIndex[name] = i
+	}
+	for name, fn := range converters {
+		fieldIndex, ok := nameToIndex[name]
+		if !ok {
+			return nil, fmt.Errorf("Member “%s” not found", name)
+		}
+		fields[fieldIndex].converter = func(in []byte, p upt) error { return fn(in, unsafe.Pointer(p)) }
+		fields[fieldIndex].flags &^= sffDefaultConverters
+		if copier := fields[fieldIndex].copier; copier != nil {
+			fields[fieldIndex].converter = withCopier(fields[fieldIndex].converter, copier) //Members that copy this member (gfs:"copyof") are still filled
+		}
+	}
+
+	return sm.CreateReader(), nil
+}
+
+// withOptions returns a copy of the StructModel whose members’ conversion functions are replaced according to the options. The cached StructModel is not modified.
+func (sm StructModel) withOptions(opts ReaderOptions) StructModel {
+	if len(opts.TimeLayouts) == 0 && opts.TimeLocation == nil && !opts.ZeroNullMembers && !opts.InternStrings && !opts.SkipNullConversions && !opts.CoalesceNulls && opts.DefaultIntBase == 0 && !opts.DBTagNames {
+		return sm
+	}
+	fields := make([]structField, len(sm.fields))
+	copy(fields, sm.fields)
+	sm.fields = fields
+
+	//Name the members by their db tags
+	if opts.DBTagNames {
+		for i, sf := range fields {
+			fields[i].name, fields[i].baseName = sf.dbName, sf.dbBaseName
+		}
+	}
+
+	//Replace the conversion functions of the time members
+	if len(opts.TimeLayouts) != 0 || opts.TimeLocation != nil {
+		sm.replaceTimeConverters(opts.TimeLayouts, opts.TimeLocation)
+	}
+
+	//Intern the strings
+	if opts.InternStrings {
+		sm.replaceStringConverters()
+	}
+
+	//Replace the conversion functions of the integer members
+	if opts.DefaultIntBase != 0 && opts.DefaultIntBase != 10 {
+		sm.replaceIntConverters(opts.DefaultIntBase)
+	}
+
+	//Skip conversions on NULL
+	if opts.SkipNullConversions {
+		for i := range fields {
+			fields[i].flags |= sffSkipNull
+		}
+	}
+
+	//Coalesce nullable members
+	if opts.CoalesceNulls {
+		for i, sf := range fields {
+			if sf.flags&sffIsNullable != 0 {
+				fields[i].converter = makeCoalescingConverter(sf.rType, sf.converter)
+				fields[i].flags &^= sffSkipNull
+			}
+		}
+	}
+
+	//Zero members on NULL
+	if opts.ZeroNullMembers {
+		for i, sf := range fields {
+			//Members converted by 2 fields (datetime2col) are skipped, as either column being NULL would clear the part set by the other
+			if sf.rType == nil || sf.rType.Kind() == reflect.Interface || sf.flags&sffSharedMember != 0 || implementsDirectly(sf.rType, lookupType.writer) {
+				continue
+			}
+			fn, t := sf.converter, sf.rType
+			fields[i].flags &^= sffSkipNull
+			fields[i].converter = func(in []byte, p upt) error {
+				if in == nil {
+					reflect.NewAt(t, unsafe.Pointer(p)).Elem().SetZero()
+				}
+				return fn(in, p)
+			}
+		}
+	}
+
+	return sm
+}
+
+// makeCoalescingConverter wraps the conversion function of a nullable struct so a NULL column leaves it marked as not NULL with its value zeroed
+func makeCoalescingConverter(t reflect.Type, fn converterFunc) converterFunc {
+	//nulltypes have IsNull (from the embedded NullInherit) first, and database/sql’s have Valid last
+	valFld, flagFld, nullFlag := t.Field(1), t.Field(0), true
+	if flagFld.Type != lookupType.nullInherit {
+		valFld, flagFld, nullFlag = t.Field(0), t.Field(1), false
+	}
+	valType, valOffset, flagOffset := valFld.Type, valFld.Offset, flagFld.Offset
+
+	return func(in []byte, p upt) error {
+		if err := fn(in, p); err != nil {
+			return err
+		}
+		if flag := (*bool)(unsafe.Add(unsafe.Pointer(p), flagOffset)); *flag == nullFlag {
+			reflect.NewAt(valType, unsafe.Add(unsafe.Pointer(p), valOffset)).Elem().SetZero()
+			*flag = !nullFlag
+		}
+		return nil
+	}
+}
+
+// replaceTimeConverters replaces the conversion functions of the time members (which must already be a copy of the cached fields) with ones using the layouts and location
+func (sm StructModel) replaceTimeConverters(timeLayouts []string, timeLocation *time.Location) {
+	layouts := append([]string(nil), timeLayouts...)
+	loc := cond(timeLocation == nil, time.UTC, timeLocation)
+	convT := func(in []byte, p upt) error { return convTimeWith(in, p, layouts, loc) }
+	convNT := func(in []byte, p upt) error { return convNTWith(in, p, loc, convT) }
+	convSNT := func(in []byte, p upt) error { return convSQLNull[time.Time](in, p, convT) }
+	for i, sf := range sm.fields {
+		if sf.flags&sffIsTime == 0 {
+			continue
+		}
+		switch {
+		case sf.rType == lookupType.time:
+			sm.fields[i].converter = convT
+		case sf.rType.Field(0).Type == lookupType.nullInherit: //nulltypes.NullTime and nulltypes.Null[time.Time]
+			sm.fields[i].converter = convNT
+		default: //sql.NullTime and sql.Null[time.Time]
+			sm.fields[i].converter = convSNT
+		}
+	}
+}
+
+// replaceIntConverters replaces the conversion functions of the integer members (which must already be a copy of the cached fields) with ones using the base.
+// Members with gfs tag options or a Validator lost sffIsInteger when they were modeled, since their conversion functions wrap the base 10 one, so they are not replaced (see ReaderOptions.DefaultIntBase).
+func (sm StructModel) replaceIntConverters(base int) {
+	for i, sf := range sm.fields {
+		if sf.flags&sffIsInteger != 0 {
+			sm.fields[i].converter = intConverterForType(sf.rType, base)
+		}
+	}
+}
+
+// intConverterForType returns the conversion function for an integer or nulltypes integer type (which must be flagged sffIsInteger) that parses in the base
+func intConverterForType(t reflect.Type, base int) converterFunc {
+	if t.Kind() != reflect.Struct {
+		return intConverterWithBase(t.Kind(), base)
+	}
+	valFld, _ := t.FieldByName("Val")
+	convI, valOffset := intConverterWithBase(valFld.Type.Kind(), base), valFld.Offset
+	return func(in []byte, p upt) error { return convI(null(in, p), upt(unsafe.Add(unsafe.Pointer(p), valOffset))) }
+}
+
+// replaceStringConverters replaces the conversion functions of the string members (which must already be a copy of the cached fields) with ones that intern the strings. All members of the reader share the same strings.
+func (sm StructModel) replaceStringConverters() {
+	interned := make(map[string]string)
+	convS := func(in []byte, p upt) error {
+		if s, ok := interned[string(in)]; ok {
+			*(*string)(p) = s
+			return nil
+		}
+		s := string(in)
+		if len(interned) < MaxInternedStrings {
+			interned[s] = s
+		}
+		*(*string)(p) = s
+		return nil
+	}
+	convNS := func(in []byte, p upt) error { return convS(null(in, p), upt(&(*nt.NullString)(p).Val)) }
+	for i, sf := range sm.fields {
+		if sf.flags&sffIsString != 0 {
+			sm.fields[i].converter = cond(sf.flags&sffIsNullable != 0, convNS, convS)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+//Generic helpers for scanning whole result sets
+
+package gofastersql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// createReaderFor creates a RowReader for type T
+func createReaderFor[T any]() (*RowReader, error) {
+	sm, err := ModelStruct((*T)(nil))
+	if err != nil {
+		return nil, err
+	}
+	return sm.CreateReader(), nil
+}
+
+/*
+ScanAllRows scans each row into a new element appended to dst, which must be a *[]T or *[]*T where T is the RowReader’s (single) type. The type of dst is only checked once, before scanning.
+
+Each element starts zeroed (or is newly allocated for *[]*T), so T cannot contain pointers that need to be initialized. rows is always closed before returning.
+The other helpers that scan whole result sets (Reader.ScanAll, Reduce, ForEachRow, ScanPooled, ScanChanCtx, ScanValidate and ScanPivot) also always close rows. Their T values also start zeroed (except for ScanPooled’s), so the same rule on pointers applies to them.
+If a row fails to scan, the rows before it are kept in dst and the error is returned. Otherwise the final rows.Err() is returned.
+A RowReaderNamed matches the columns by name on the first row only (ex: for SELECT * with reordered columns), as with its other scans.
+*/
+func (rr *RowReader) ScanAllRows(rows *sql.Rows, dst any) error {
+	defer runSafeCloseRow(rows)
+
+	//Check the type of dst
+	if len(rr.sm.rTypes) != 1 {
+		return errors.New("RowReader must have a single type")
+	}
+	rType, t := rr.sm.rTypes[0], reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Slice || (t.Elem().Elem() != rType && t.Elem().Elem() != reflect.PointerTo(rType)) {
+		return fmt.Errorf("dst type is incorrect (%s)!=(*[]%s or *[]*%s)", fmt.Sprint(t), rType.String(), rType.String())
+	} else if reflect.ValueOf(dst).IsNil() {
+		return ErrPointerNotInitialized
+	}
+	isPointerSlice := t.Elem().Elem() != rType
+
+	//Scan each row into the next element
+	sliceVal := reflect.ValueOf(dst).Elem()
+	header := (*struct {
+		data     unsafe.Pointer
+		len, cap int
+	})(interface2Pointer(dst))
+	proto, size := reflect.New(rType).Interface(), rType.Size()
+	outPointers := make([]any, 1)
+	for runRowNext(rows) {
+		var p unsafe.Pointer
+		if isPointerSlice {
+			p = reflect.New(rType).UnsafePointer()
+		} else {
+			if header.len == header.cap {
+				sliceVal.Grow(1)
+			}
+			p = unsafe.Add(header.data, uintptr(header.len)*size)
+			reflect.NewAt(rType, p).Elem().SetZero() //Only the element being appended is zeroed, as the rest of the capacity may be used by other slices
+		}
+
+		outPointers[0] = pointer2Interface(proto, p)
+		if err := rr.ScanRowsNC(rows, outPointers...); err != nil {
+			return err
+		}
+
+		if isPointerSlice {
+			ptrs := (*[]unsafe.Pointer)(interface2Pointer(dst))
+			*ptrs = append(*ptrs, p)
+		} else {
+			header.len++
+		}
+	}
+	return rows.Err()
+}
+
+/*
+Reader scans rows into a T, returning the values directly, so the type is checked at compile time instead of on every scan. It holds an index based RowReader, so the same rules apply, and it is NOT concurrency safe.
+Each scan starts with a zeroed T (see RowReader.ScanAllRows).
+*/
+type Reader[T any] struct {
+	rr *RowReader
+}
+
+// NewReader creates a Reader for T
+func NewReader[T any]() (*Reader[T], error) {
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	return &Reader[T]{rr}, nil
+}
+
+// Scan scans the current row into a T. This does not call rows.Next() or rows.Close(). See RowReader.ScanRows
+func (r *Reader[T]) Scan(rows *sql.Rows) (T, error) {
+	var v T
+	err := r.rr.DoScan(rows, []any{&v}, nil, false, false)
+	return v, err
+}
+
+// ScanAll scans all the remaining rows into a slice of T. See RowReader.ScanAllRows
+func (r *Reader[T]) ScanAll(rows *sql.Rows) ([]T, error) {
+	var out []T
+	err := r.rr.ScanAllRows(rows, &out)
+	return out, err
+}
+
+/*
+Reduce scans each row into a T and folds it into the accumulator (starting with init) via fn, without storing the rows. A single RowReader is used for all the rows. See RowReader.ScanAllRows
+*/
+func Reduce[T, A any](rows *sql.Rows, init A, fn func(A, T) A) (A, error) {
+	defer runSafeCloseRow(rows)
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return init, err
+	}
+
+	acc := init
+	for runRowNext(rows) {
+		var v T
+		if err := rr.ScanRowsNC(rows, &v); err != nil {
+			return acc, err
+		}
+		acc = fn(acc, v)
+	}
+	return acc, rows.Err()
+}
+
+/*
+ForEachRow scans each row into the same *T and passes it to fn, without accumulating the rows. fn must not retain the *T as it is overwritten by the next row. A single RowReader is used for all the rows. See RowReader.ScanAllRows
+
+The *T is only zeroed before the first row. If fn returns an error, scanning stops and that error is returned.
+*/
+func ForEachRow[T any](rows *sql.Rows, fn func(*T) error) error {
+	defer runSafeCloseRow(rows)
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return err
+	}
+
+	v := new(T)
+	for runRowNext(rows) {
+		if err := rr.ScanRowsNC(rows, v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+/*
+ScanPooled scans each row into a *T taken from pool and passes it to fn. The *T is returned to the pool after fn returns, so fn must not retain it. A single RowReader is used for all the rows.
+
+pool must only contain *T values. If the pool is empty and has no New function, a new T is allocated. As the T values are reused, any pointers they contain must already be initialized (see ScanRows). See RowReader.ScanAllRows for the other rules. If fn returns an error, scanning stops and that error is returned.
+*/
+func ScanPooled[T any](rows *sql.Rows, pool *sync.Pool, fn func(*T) error) error {
+	defer runSafeCloseRow(rows)
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return err
+	}
+
+	for runRowNext(rows) {
+		v, _ := pool.Get().(*T)
+		if v == nil {
+			v = new(T)
+		}
+		err := rr.ScanRowsNC(rows, v)
+		if err == nil {
+			err = fn(v)
+		}
+		pool.Put(v)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+/*
+ScanChanCtx scans each row into a new T and sends it to ch, blocking until the send is accepted or ctx is done. A single RowReader is used for all the rows. See RowReader.ScanAllRows
+
+ch is never closed.
+If ctx is done then ctx.Err() is returned, otherwise the error from scanning or the final rows.Err() is returned.
+*/
+func ScanChanCtx[T any](ctx context.Context, rows *sql.Rows, ch chan<- T) error {
+	defer runSafeCloseRow(rows)
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return err
+	}
+
+	for runRowNext(rows) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var v T
+		if err := rr.ScanRowsNC(rows, &v); err != nil {
+			return err
+		}
+		select {
+		case ch <- v:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return rows.Err()
+}
+
+/*
+ScanValidate scans each row into a new T and runs validate on it. Rows that pass are returned in validRows and the errors of rows that fail are returned in rowErrors, keyed by their 0 based row index. A single RowReader is used for all the rows. See RowReader.ScanAllRows
+
+Conversion errors are not validation errors: they stop scanning and are returned as err, along with the rows processed so far.
+*/
+func ScanValidate[T any](rows *sql.Rows, validate func(T) error) (validRows []T, rowErrors map[int]error, err error) {
+	defer runSafeCloseRow(rows)
+	rr, err := createReaderFor[T]()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rowErrors = make(map[int]error)
+	for rowIndex := 0; runRowNext(rows); rowIndex++ {
+		var v T
+		if err := rr.ScanRowsNC(rows, &v); err != nil {
+			return validRows, rowErrors, err
+		}
+		if err := validate(v); err != nil {
+			rowErrors[rowIndex] = err
+		} else {
+			validRows = append(validRows, v)
+		}
+	}
+	return validRows, rowErrors, rows.Err()
+}
+
+/*
+ScanPivot scans all rows into a 2 dimensional map (a cross-tab) of [rowKeyCol][colKeyCol]valCol, using the columns’ names. Other columns are ignored.
+
+NULL columns are treated as empty strings. If a (rowKey, colKey) pair occurs in more than 1 row, the last row’s value is kept. See RowReader.ScanAllRows
+*/
+func ScanPivot(rows *sql.Rows, rowKeyCol, colKeyCol, valCol string) (map[string]map[string]string, error) {
+	defer runSafeCloseRow(rows)
+
+	//Find the columns
+	colNames, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	colIndexes := [3]int{-1, -1, -1}
+	for i, name := range [3]string{rowKeyCol, colKeyCol, valCol} {
+		for colIndex, colName := range colNames {
+			if colName == name {
+				colIndexes[i] = colIndex
+				break
+			}
+		}
+		if colIndexes[i] == -1 {
+			return nil, fmt.Errorf("Column “%s” not found", name)
+		}
+	}
+
+	//Scan the rows into the map
+	rawBytesArr := make([]sql.RawBytes, len(colNames))
+	rawBytesAny := make([]any, len(colNames))
+	for i := range rawBytesArr {
+		rawBytesAny[i] = &rawBytesArr[i]
+	}
+	ret := make(map[string]map[string]string)
+	for runRowNext(rows) {
+		//Nil out all values in rawBytes in case sql attempts to read a non []byte into them (see RowReader.scanRaw)
+		for i := range rawBytesArr {
+			rawBytesArr[i] = nil
+		}
+		if err := rows.Scan(rawBytesAny...); err != nil {
+			return nil, err
+		}
+		rowKey := string(rawBytesArr[colIndexes[0]])
+		cols := ret[rowKey]
+		if cols == nil {
+			cols = make(map[string]string)
+			ret[rowKey] = cols
+		}
+		cols[string(rawBytesArr[colIndexes[1]])] = string(rawBytesArr[colIndexes[2]])
+	}
+	return ret, rows.Err()
+}
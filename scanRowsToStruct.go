@@ -17,15 +17,55 @@ Both ScanRow(s) (plural and singular) functions only accept sql.Rows and not sql
 
 The SRErr() and *.ScanRowWErr*() helper functions exist to help emulate sql.Row.Scan error handling functionality.
 
-GoFasterSQL supports the following types, including: typedef derivatives, nested use in structures (including pointers to the types), and nullable derivatives (see nulltypes package).
-  - string, []byte, sql.RawBytes (RawBytes converted to []byte for singular RowScan functions)
-  - bool
+GoFasterSQL supports the following types, including: typedef derivatives, nested use in structures (including pointers to the types), and nullable derivatives (see nulltypes package, whose generic nulltypes.Null[T] makes any supported type nullable, ex: Null[MyEnum]).
+  - string, []byte, sql.RawBytes (RawBytes converted to []byte for singular RowScan functions; typedef derivatives of sql.RawBytes cannot be told apart from []byte, so they are always copied like []byte)
+  - [N]byte, [N]rune (fixed width, ex: CHAR(N) columns; runes are decoded from UTF-8, shorter columns are zero padded, and longer columns return an error)
+  - bool (true for non-zero integers and case-insensitive “true”, “t”, “yes”, and “y”; false for everything else, including NULL and empty)
   - int, int8, int16, int32, int64
   - uint, uint8, uint16, uint32, uint64
   - float32, float64
-  - time.Time (also accepts unix timestamps ; does not currently accept typedef derivatives)
+  - time.Time (also accepts unix timestamps, including negative ones, and RFC3339 text with optional fractional seconds, which keeps its time zone ; does not currently accept typedef derivatives)
+  - time.Duration (integer nanoseconds; see the gfs:"duration" tag option for other units)
+  - gofastersql.LazyTime (stores the raw time text and only parses it when LazyTime.Time() or LazyTime.TimeWith() is called)
+  - sync/atomic: Int32, Int64, Uint32, Uint64, Bool
+  - sql.NullString, sql.NullInt16, sql.NullInt32, sql.NullInt64, sql.NullByte, sql.NullFloat64, sql.NullBool, sql.NullTime (NULL sets Valid to false and the value to its zero value)
+  - sql.Null[T] (Go 1.22+, for any supported scalar T except sql.RawBytes)
+  - Types implementing gofastersql.BytesScanner that are not otherwise supported (ScanBytes([]byte) error: receives the raw column bytes without the “any” boxing of sql.Scanner)
+  - Types implementing gofastersql.BytesConsumer that are not otherwise supported (ConsumeBytes([]byte) (int, error): for composite/packed columns, returns an error for the member if the column was not fully consumed)
+  - Types implementing sql.Scanner (ex: decimal.Decimal) that are not otherwise supported (Scan() receives a []byte that is only valid until the next scan, or nil for NULL)
+  - Types implementing encoding.TextUnmarshaler (ex: netip.Addr, big.Int) that are not otherwise supported (NULL sets the zero value; nil pointers to these types are allocated, or left nil on NULL)
+  - Types implementing encoding.BinaryUnmarshaler that are not otherwise supported (receives a copy of the raw column; otherwise the same as encoding.TextUnmarshaler)
+  - io.Writer and types implementing it (ex: bytes.Buffer, strings.Builder) (the column is appended via Write(); NULL writes nothing)
   - struct
 
+For types implementing more than one of the conversion interfaces, the order of precedence is: RegisterConverter, the built-in types, gofastersql.BytesScanner, gofastersql.BytesConsumer, io.Writer, sql.Scanner, encoding.TextUnmarshaler, and then encoding.BinaryUnmarshaler. The interfaces are only used when their methods are declared on the type itself, so structures that embed a type implementing one (ex: struct{ time.Time; By string }) are still recursed into.
+
+Members tagged with gfs:"-" or db:"-", and members of func type, are skipped (they do not take up a column). With ReaderOptions.DBTagNames, the name in a member’s db tag (ex: db:"full_name") replaces its Go name for named matching (see RowReaderNamed).
+
+Members can be given options through a “gfs” struct tag (multiple options are comma separated):
+  - gfs:"json": The column is decoded into the member via json.Unmarshal instead of recursing into it (works on structs, maps, slices, etc). The member only takes up 1 column. NULL sets the member to its zero value. Spatial columns can be scanned by selecting them as GeoJSON (ex: ST_AsGeoJSON(geom)) into a json member. A “json” option in the db tag (ex: db:",json") is the same as gfs:"json".
+  - gfs:"lower", gfs:"upper": Converts a string member to lowercase/uppercase.
+  - gfs:"decimal": Returns an error if a string member does not receive a valid decimal number (ex: NUMERIC/DECIMAL columns). The exact text is kept.
+  - gfs:"onoverflow:MODE": For integer members. Determines what happens when the column is outside of the member’s range. MODE is “error” (the default), “clamp” (set to the closest value in range), or “skip” (leave the prior value).
+  - gfs:"base:N": For integer and nulltypes integer members. Parses the column in base N (2 to 36, ex: 16 for hexadecimal columns), taking precedence over ReaderOptions.DefaultIntBase. It must be the first option, as it replaces the conversion function.
+  - gfs:"jsonvalidate": For byte slice (ex: json.RawMessage) and string members. Returns an error if the column is not well-formed JSON. Without this, the column is copied without validation.
+  - gfs:"autobin": For byte slice members. Columns that are hexadecimal literals starting with “0x” (ex: MySQL’s --binary-as-hex client mode) are hex decoded. Other columns are copied as is.
+  - gfs:"stream": For io.Reader members. The member is set to a reader over the driver’s buffer for the column instead of a copy (nil for NULL), which is only valid until the next scan (like sql.RawBytes). The singular ScanRow functions use a copy. database/sql requires drivers to materialize column values, so this avoids a copy of large BLOBs but cannot stream them from the server.
+  - gfs:"maxlen:LENGTH[:error]": For string and byte slice members. Columns longer than LENGTH bytes are truncated (without splitting the UTF-8 characters of strings), or return an error if “:error” is given.
+  - gfs:"enum:VAL1|VAL2|...": Returns an error if a string member does not receive one of the listed values.
+  - gfs:"enumdefault:VAL": Sets a string member to VAL when the column is NULL, or when its value is not in the list of a preceding enum option (ex: gfs:"enum:on|off,enumdefault:unknown").
+  - gfs:"csv[:SEPARATOR]": Splits the column (ex: GROUP_CONCAT or Postgres arrays) into the elements of a slice member. The separator defaults to “,” and surrounding braces are removed. NULL elements are converted as NULL (ex: []nulltypes.NullInt64 elements become IsNull).
+  - gfs:"bits": For bool slice members. Converts a string of bits (ex: “10110”) into one element per character (“1” is true and “0” is false).
+  - gfs:"kv:PAIR_SEPARATOR:KEY_VALUE_SEPARATOR": For map[string]string members. Parses “key=value” pairs (ex: “a=1;b=2”) into the map. The separators default to “;” and “=”.
+  - gfs:"duration:UNIT": For time.Duration members. UNIT is the unit of the integer column (ns, us, ms, s, m, or h), or “string” to parse Go duration strings (ex: “1h30m”). Without this, integer columns are nanoseconds.
+  - gfs:"pginterval": For time.Duration members. Converts Postgres interval text (ex: “01:30:00”, “1 day 02:03:04”) into the duration. Days are 24 hours. Months and years return an error as they have no fixed duration.
+  - gfs:"datetime2col": For time.Time members. The member consumes 2 columns, a DATE followed by a TIME (within a single day), which are combined. For named matching, the columns are named “MEMBER.date” and “MEMBER.time”.
+  - gfs:"copyof:MEMBER": The member consumes no column and is instead converted from the column of MEMBER (a non-pointer member of the same structure that consumes a single column), after it. For example, a time.Time member and a string member tagged gfs:"copyof:TIME_MEMBER" both receive the same DATETIME column. Other options on the member still apply.
+  - gfs:"resolve:NAME:DISCRIMINATOR": For interface members. The TypeResolver registered as NAME (see RegisterTypeResolver) picks the concrete type to convert the column into from the value of the DISCRIMINATOR string member.
+  - gfs:"concrete": For interface members that hold a non-nil pointer before scanning. The column is converted into the pointed to value using the concrete type found at scan time (structures via json.Unmarshal, as the number of columns is fixed when the model is created).
+  - gfs:"present:MEMBER": For structure members. Sets the bool MEMBER of the same parent structure (which must be tagged gfs:"-") to if any of the structure’s columns are not NULL (ex: to tell if the optional side of a LEFT JOIN matched).
+  - gfs:"nilonnull": For structure pointer members. If all the structure’s columns are NULL (ex: the unmatched side of a LEFT JOIN) the pointer is set to nil. Otherwise, if the pointer is nil, a new structure is allocated for it.
+
 Optimization Information:
   - The sole instance of reflection following a ModelStruct call occurs during the ScanRow(s) functions, where a verification ensures that the outPointers types align with the types specified in ModelStruct (the *NC versions [DoScan(runCheck=false)] skip this check).
   - Creating a StructModel from a single structure requires much less overhead than the alternatives.
@@ -91,6 +131,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -101,6 +142,14 @@ type RowReader struct {
 	rawBytesAny []any            //This holds pointers to each member of rawBytesArr
 	pointers    []unsafe.Pointer //Used to calculate struct pointer locations. Index 0 is the root struct pointer
 	rrType      rowReaderType
+	timings     debugTimings //Only used with the gofastersql_debug build tag
+	allNull     []bool       //Scratch space for determining which struct pointers have all NULL columns (indexed like pointers). Only allocated if a struct pointer is nilOnNull
+	onField     FieldHook    //Called for each member after it is converted. See RowReader.OnField()
+	safeConvert bool         //If panics in conversion functions are recovered into errors. See RowReader.SafeConverters()
+	present     []bool       //Scratch space for determining which structures with presence members have a non-NULL column (indexed like StructModel.presences+1). Only allocated if there are presence members
+	lazy        *lazyModel   //The member lookups shared by the LazyRows of this reader. Only created on the first RowReader.ScanLazy()
+	keepRawRow  bool         //If a copy of the columns is kept after each scan. See ReaderOptions.KeepRawRow
+	lastRawRow  [][]byte     //The copy of the columns of the last scan. See RowReader.LastRawRow()
 }
 
 // rowReaderType specifies extensions onto RowReader
@@ -119,9 +168,60 @@ func (sm StructModel) CreateReader() *RowReader {
 		rba[i] = &rb[i]
 	}
 
-	return &RowReader{sm, rb, rba, make([]unsafe.Pointer, len(sm.pointers)+1), rrtStandard}
+	var allNull []bool
+	for _, p := range sm.pointers {
+		if p.nilOnNull {
+			allNull = make([]bool, len(sm.pointers)+1)
+			break
+		}
+	}
+
+	var present []bool
+	if len(sm.presences) != 0 {
+		present = make([]bool, len(sm.presences)+1)
+	}
+
+	rr := newRowReader(sm, rb, rba, make([]unsafe.Pointer, len(sm.pointers)+1), allNull, present)
+	return &rr
 }
 
+// newRowReader returns a standard RowReader using the buffers, with all settings at their defaults
+func newRowReader(sm StructModel, rawBytesArr []sql.RawBytes, rawBytesAny []any, pointers []unsafe.Pointer, allNull, present []bool) RowReader {
+	return RowReader{sm, rawBytesArr, rawBytesAny, pointers, rrtStandard, debugTimings{}, allNull, nil, false, present, nil, false, nil}
+}
+
+// copySettings copies the settings that change how rows are scanned (see RowReader.OnField, RowReader.SafeConverters and ReaderOptions.KeepRawRow) from another RowReader
+func (rr *RowReader) copySettings(from *RowReader) {
+	rr.onField, rr.safeConvert, rr.keepRawRow = from.onField, from.safeConvert, from.keepRawRow
+}
+
+// ErrPointerNotInitialized is the error given for nil pointers (to members or structures) during conversion
+var ErrPointerNotInitialized = errors.New("Pointer not initialized")
+
+// ErrTooFewColumns and ErrTooManyColumns are wrapped into the error given when the number of columns in the query does not match the number of fields in the model (see errors.Is)
+var (
+	ErrTooFewColumns  = errors.New("Too few columns")
+	ErrTooManyColumns = errors.New("Too many columns")
+)
+
+// ErrMultipleRows is the error given by RowReader.ScanOne when the query returns more than 1 row
+var ErrMultipleRows = errors.New("Query returned multiple rows")
+
+// columnCountError returns the error for a query with numColumns columns scanned into a model with numFields fields, wrapping ErrTooFewColumns or ErrTooManyColumns
+func columnCountError(numColumns, numFields int) error {
+	return fmt.Errorf("Number of columns in row (%d) does not match number of expected fields (%d): %w", numColumns, numFields, cond(numColumns < numFields, ErrTooFewColumns, ErrTooManyColumns))
+}
+
+// ErrorFormatter formats a conversion error for a member (or struct pointer) into a line of the error returned by the ScanRow(s) functions. The default produces “Error on NAME: ERROR”.
+// Replace it to localize or restructure the messages (ex: as JSON). It is called by every scan that has errors, so replace it before any readers are in use.
+var ErrorFormatter = func(fieldName string, err error) string {
+	return fmt.Sprintf("Error on %s: %s", fieldName, err.Error())
+}
+
+// ErrorSeparator joins the formatted conversion errors (see ErrorFormatter) in the error returned by the ScanRow(s) functions. Defaults to a newline.
+// Set it to something like "; " to keep a row’s errors on a single log line. Like ErrorFormatter, it is read during scans and should only be set at startup.
+var ErrorSeparator = "\n"
+
 // SRErr converts a (*sql.Rows, error) tuple into a single variable to pass to *.ScanRowWErr*() functions
 func SRErr(r *sql.Rows, err error) SRErrStruct { return SRErrStruct{r, err} }
 
@@ -151,6 +251,34 @@ func (rr *RowReader) DoScan(rows *sql.Rows, outPointers []any, err error, runChe
 	}
 
 	//Make sure the outPointers types match
+	if err := rr.checkOutPointers(outPointers, runCheck); err != nil {
+		return err
+	}
+
+	//If a single row, make sure to open it
+	if isSingleRow && !runRowNext(rows) {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	//Run the scan and conversion
+	if err := rr.scanAndConvert(rows, outPointers, isSingleRow); err != nil {
+		return err
+	}
+
+	//If not a single row then nothing more to do
+	if !isSingleRow {
+		return nil
+	}
+
+	//Finish closing a single row
+	return runCloseRow(rows)
+}
+
+// checkOutPointers makes sure there is an outPointers variable for each type, and if runCheck is true, that they are pointers to the types
+func (rr *RowReader) checkOutPointers(outPointers []any, runCheck bool) error {
 	if len(outPointers) != len(rr.sm.rTypes) {
 		return fmt.Errorf("outPointers is incorrect length %d!=%d", len(outPointers), len(rr.sm.rTypes))
 	}
@@ -162,19 +290,24 @@ func (rr *RowReader) DoScan(rows *sql.Rows, outPointers []any, err error, runChe
 			}
 		}
 	}
+	return nil
+}
 
-	//If a single row, make sure to open it
-	if isSingleRow && !runRowNext(rows) {
-		if err := rows.Err(); err != nil {
-			return err
-		}
-		return sql.ErrNoRows
+// scanAndConvert runs the sql.Rows.Scan() and the conversion into the outPointers variables. No checks are done on outPointers.
+func (rr *RowReader) scanAndConvert(rows *sql.Rows, outPointers []any, isSingleRow bool) error {
+	if err := rr.scanRaw(rows); err != nil {
+		return err
 	}
+	return rr.convert(outPointers, isSingleRow)
+}
 
+// scanRaw runs the sql.Rows.Scan() into the RawBytes (matching the columns first for a RowReaderNamed)
+func (rr *RowReader) scanRaw(rows *sql.Rows) error {
 	//Nil out all values in rawBytes in case sql attempts to read a non []byte into them (security vulnerability bug in golang sql code)
 	for i := range rr.rawBytesArr {
 		rr.rawBytesArr[i] = nil
 	}
+	rr.lastRawRow = nil
 
 	//Handle extensions
 	if rr.rrType != rrtStandard {
@@ -187,19 +320,48 @@ func (rr *RowReader) DoScan(rows *sql.Rows, outPointers []any, err error, runChe
 	}
 
 	//Run the scan and conversion
+	scanStart := debugStart()
 	if err := rows.Scan(rr.rawBytesAny...); err != nil {
-		return err
-	} else if err := rr.convert(outPointers, isSingleRow); err != nil {
+		//Column count mismatches are only checked for on failure so successful scans do not pay for the rows.Columns() call
+		if colNames, colErr := rows.Columns(); colErr == nil && len(colNames) != len(rr.rawBytesAny) {
+			return columnCountError(len(colNames), len(rr.rawBytesAny))
+		}
 		return err
 	}
+	rr.timings.addScan(scanStart)
 
-	//If not a single row then nothing more to do
-	if !isSingleRow {
-		return nil
+	//Keep a copy of the columns
+	if rr.keepRawRow {
+		rr.lastRawRow = copyRawBytes(rr.rawBytesArr)
 	}
+	return nil
+}
 
-	//Finish closing a single row
-	return runCloseRow(rows)
+// copyRawBytes copies the columns into a single new buffer (NULL columns stay nil)
+func copyRawBytes(rawBytesArr []sql.RawBytes) [][]byte {
+	size := 0
+	for _, rb := range rawBytesArr {
+		size += len(rb)
+	}
+	buf := make([]byte, 0, size)
+	cols := make([][]byte, len(rawBytesArr))
+	for i, rb := range rawBytesArr {
+		if rb != nil {
+			start := len(buf)
+			buf = append(buf, rb...)
+			cols[i] = buf[start:len(buf):len(buf)]
+		}
+	}
+	return cols
+}
+
+/*
+LastRawRow returns a copy of the columns (nil for NULL) from the last scan, in the order they were received, so the original row can be relayed or re-serialized alongside the converted values. This is only kept if the reader was created with ReaderOptions.KeepRawRow.
+
+The copy is independent of the reader’s buffers, so it stays valid after later scans (which each create a new copy). Returns nil if no copy is kept or if the last scan failed before receiving the row.
+*/
+func (rr *RowReader) LastRawRow() [][]byte {
+	return rr.lastRawRow
 }
 
 // ScanRows does an sql.Rows.Scan into the outPointers variables.
@@ -216,6 +378,13 @@ func (rr *RowReader) ScanRowsNC(rows *sql.Rows, outPointers ...any) error {
 	return rr.DoScan(rows, outPointers, nil, false, false)
 }
 
+// ScanRowsRaw does an sql.Rows.Scan into the outPointers variables without ANY checks on outPointers (neither their types nor their count).
+//
+// Unsafe! Passing the incorrect number or types of variables can corrupt memory or crash. Only use this in hot loops where the variables have already been validated (ex: via a successful ScanRows call).
+func (rr *RowReader) ScanRowsRaw(rows *sql.Rows, outPointers ...any) error {
+	return rr.scanAndConvert(rows, outPointers, false)
+}
+
 // ScanRow does an sql.Rows.Scan into the outPointers variables for a single row.
 //
 // Just runs: rr.DoScan(rows, outPointers, nil, true, true)
@@ -230,6 +399,37 @@ func (rr *RowReader) ScanRowNC(rows *sql.Rows, outPointers ...any) error {
 	return rr.DoScan(rows, outPointers, nil, false, true)
 }
 
+/*
+ScanOne does an sql.Rows.Scan into the outPointers variables for a query that must return exactly 1 row. It is the same as ScanRow (including closing rows), except that ErrMultipleRows is returned if there is a second row.
+
+The outPointers variables are still filled from the first row when ErrMultipleRows is returned.
+*/
+func (rr *RowReader) ScanOne(rows *sql.Rows, outPointers ...any) error {
+	defer runSafeCloseRow(rows)
+	if err := rr.checkOutPointers(outPointers, true); err != nil {
+		return err
+	}
+
+	//Scan the first row
+	if !runRowNext(rows) {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rr.scanAndConvert(rows, outPointers, true); err != nil {
+		return err
+	}
+
+	//Make sure there is not a second row
+	if runRowNext(rows) {
+		return ErrMultipleRows
+	} else if err := rows.Err(); err != nil {
+		return err
+	}
+	return runCloseRow(rows)
+}
+
 // ScanRowWErr : See rr.ScanRow and SRErr
 //
 // Just runs: rr.DoScan(rowsErr.r, outPointers, rowsErr.err, true, true)
@@ -287,6 +487,64 @@ func ScanRowWErr(rowsErr SRErrStruct, outPointers ...any) error {
 	return ScanRow(rowsErr.r, outPointers...)
 }
 
+// ScanScratch holds buffers that ScanRowBuf reuses between calls, which are grown as needed. The zero value is ready to use. ScanScratch is NOT concurrency safe.
+type ScanScratch struct {
+	rr          RowReader
+	rawBytesArr []sql.RawBytes
+	rawBytesAny []any //This holds pointers to each member of rawBytesArr
+	pointers    []unsafe.Pointer
+	allNull     []bool
+	present     []bool
+}
+
+// ScanRowBuf is the same as ScanRow, except the RowReader and its buffers are reused from scratch instead of being allocated on every call. This is useful for a loop of single row scans with varying variables.
+func ScanRowBuf(scratch *ScanScratch, rows *sql.Rows, outPointers ...any) error {
+	if sm, err := scanRowModelStruct(rows, outPointers); err != nil {
+		return err
+	} else {
+		return scratch.reader(*sm).DoScan(rows, outPointers, nil, false, true)
+	}
+}
+
+// reader returns the scratch’s RowReader set up for the StructModel (see StructModel.CreateReader)
+func (s *ScanScratch) reader(sm StructModel) *RowReader {
+	numFields := len(sm.fields)
+	if cap(s.rawBytesArr) < numFields {
+		s.rawBytesArr = make([]sql.RawBytes, numFields)
+		s.rawBytesAny = make([]any, numFields)
+		for i := range s.rawBytesArr {
+			s.rawBytesAny[i] = &s.rawBytesArr[i]
+		}
+	}
+	s.pointers = growScratch(s.pointers, len(sm.pointers)+1)
+
+	var allNull []bool
+	for _, p := range sm.pointers {
+		if p.nilOnNull {
+			s.allNull = growScratch(s.allNull, len(sm.pointers)+1)
+			allNull = s.allNull
+			break
+		}
+	}
+
+	var present []bool
+	if len(sm.presences) != 0 {
+		s.present = growScratch(s.present, len(sm.presences)+1)
+		present = s.present
+	}
+
+	s.rr = newRowReader(sm, s.rawBytesArr[:numFields], s.rawBytesAny[:numFields], s.pointers, allNull, present)
+	return &s.rr
+}
+
+// growScratch returns the slice resized to length n, reallocating it only if its capacity is too small
+func growScratch[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
+
 // Convert the read sql data into the output variables
 func (rr *RowReader) convert(outPointers []any, isSingleRow bool) error {
 	//Get the outputPointer
@@ -304,21 +562,39 @@ func (rr *RowReader) convert(outPointers []any, isSingleRow bool) error {
 	}
 
 	//Determine pointer indexes
+	pointersStart := debugStart()
 	var errs []string
+	if r.allNull != nil {
+		r.markAllNullPointers()
+	}
 	r.pointers[0] = outPointer
 	for i, p := range r.sm.pointers {
 		newPtr := unsafe.Pointer(nil)
 		if r.pointers[p.parentIndex] != nil {
-			newPtr = *(*unsafe.Pointer)(unsafe.Add(r.pointers[p.parentIndex], p.offset))
-			if newPtr == nil {
-				errs = append(errs, fmt.Sprintf("Error on %s: %s", p.name, "Pointer not initialized"))
+			ptrLoc := (*unsafe.Pointer)(unsafe.Add(r.pointers[p.parentIndex], p.offset))
+			if p.nilOnNull {
+				if r.allNull[i+1] {
+					*ptrLoc = nil
+				} else if *ptrLoc == nil {
+					*ptrLoc = reflect.New(p.rType).UnsafePointer()
+				}
+			}
+
+			newPtr = *ptrLoc
+			if newPtr == nil && !p.nilOnNull {
+				errs = append(errs, ErrorFormatter(p.name, ErrPointerNotInitialized))
 			}
 		}
 
 		r.pointers[i+1] = newPtr
 	}
+	if r.present != nil {
+		r.setPresenceMembers()
+	}
+	rr.timings.addPointers(pointersStart)
 
 	//Fill in data
+	convertStart := debugStart()
 	for i, sf := range r.sm.fields {
 		//If parentPointer is not set then error was already issued
 		parentPointer := r.pointers[sf.pointerIndex]
@@ -329,28 +605,159 @@ func (rr *RowReader) convert(outPointers []any, isSingleRow bool) error {
 		//Get pointer to the output data
 		p := unsafe.Add(parentPointer, sf.offset)
 		if sf.isPointer {
+			if ptrLoc := (*unsafe.Pointer)(p); *ptrLoc == nil && sf.flags&sffAllocPointer != 0 {
+				if r.rawBytesArr[i] == nil {
+					continue
+				}
+				*ptrLoc = reflect.New(sf.rType).UnsafePointer()
+			}
 			if p = *(*unsafe.Pointer)(p); p == nil {
-				errs = append(errs, fmt.Sprintf("Error on %s: %s", sf.name, "Pointer not initialized"))
+				errs = append(errs, ErrorFormatter(sf.name, ErrPointerNotInitialized))
 				continue
 			}
 		}
 
-		//If rawBytes and isSingleRow then change output func to use a byte array instead
+		//Skip NULL columns if requested
+		if sf.flags&sffSkipNull != 0 && r.rawBytesArr[i] == nil {
+			continue
+		}
+
+		//If rawBytes and isSingleRow then change output func to use a byte array (or a reader over a copy for gfs:"stream") instead
 		cFunc := sf.converter
 		if isSingleRow && (sf.flags&sffIsRawBytes != 0) {
-			cFunc = cond(sf.flags&sffIsNullable != 0, cvNBA, convByteArray)
+			cFunc = cond(sf.rType == lookupType.reader, convStreamCopy, cond(sf.flags&sffIsNullable != 0, cvNBA, convByteArray))
 		}
 
 		//Run the conversion function
-		if err := cFunc(r.rawBytesArr[i], upt(p)); err != nil {
-			errs = append(errs, fmt.Sprintf("Error on %s: %s", sf.name, err.Error()))
+		var err error
+		if r.safeConvert {
+			err = runConverterRecovered(cFunc, r.rawBytesArr[i], upt(p))
+		} else {
+			err = cFunc(r.rawBytesArr[i], upt(p))
+		}
+		if err != nil {
+			errs = append(errs, ErrorFormatter(sf.name, err))
+		} else if r.onField != nil && sf.rType != nil {
+			if err := r.onField(sf.name, reflect.NewAt(sf.rType, p).Interface()); err != nil {
+				errs = append(errs, ErrorFormatter(sf.name, err))
+			}
 		}
 	}
+	rr.timings.addConvert(convertStart)
 
 	if len(errs) == 0 {
 		return nil
 	}
-	return errors.New(strings.Join(errs, "\n"))
+	return errors.New(strings.Join(errs, ErrorSeparator))
+}
+
+// ColumnCounts returns the number of columns the RowReader scans, and how many of them are converted into members. These only differ for a RowReaderNamed that discards columns (see CreateReaderNamedSubset) after its columns are matched (on its first scan).
+func (rr *RowReader) ColumnCounts() (numColumns, numMapped int) {
+	for _, sf := range rr.sm.fields {
+		if sf.rType != nil {
+			numMapped++
+		}
+	}
+	return len(rr.sm.fields), numMapped
+}
+
+// ColumnTypeInfo correlates a query’s column with the member it is scanned into. See RowReader.ColumnTypeMapping()
+type ColumnTypeInfo struct {
+	Field  string //The flattened name of the member (empty if the column is discarded)
+	Column string //The name of the column
+	DBType string //The database type name of the column (see sql.ColumnType.DatabaseTypeName)
+}
+
+// ColumnTypeMapping returns the database types of the query’s columns matched to the members they are scanned into, in column order. For a RowReaderNamed, this should be called after its columns are matched (on its first scan).
+func (rr *RowReader) ColumnTypeMapping(rows *sql.Rows) ([]ColumnTypeInfo, error) {
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	} else if len(colTypes) != len(rr.sm.fields) {
+		return nil, columnCountError(len(colTypes), len(rr.sm.fields))
+	}
+
+	ret := make([]ColumnTypeInfo, len(colTypes))
+	for i, ct := range colTypes {
+		ret[i] = ColumnTypeInfo{cond(rr.sm.fields[i].rType == nil, "", rr.sm.fields[i].name), ct.Name(), ct.DatabaseTypeName()}
+	}
+	return ret, nil
+}
+
+// FieldHook is called for each member after it is successfully converted. name is the member’s flattened name and ptr is a pointer to the member (ex: *string). See RowReader.OnField()
+type FieldHook func(name string, ptr any) error
+
+// OnField sets a hook that is called for each member after it is converted, for cross-cutting post-processing (ex: decryption or redaction). Errors returned by the hook are returned like conversion errors. Pass nil to remove the hook.
+func (rr *RowReader) OnField(fn FieldHook) {
+	rr.onField = fn
+}
+
+// SafeConverters sets if panics in conversion functions are recovered and returned as errors for their members, instead of crashing the scan. This is off by default for performance.
+func (rr *RowReader) SafeConverters(on bool) {
+	rr.safeConvert = on
+}
+
+// runConverterRecovered runs a conversion function and returns a panic as an error
+func runConverterRecovered(fn converterFunc, in []byte, p upt) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Panic in converter: %v", r)
+		}
+	}()
+	return fn(in, p)
+}
+
+// ScanTimings holds the accumulated time spent in each phase of a RowReader’s scans. See RowReader.Timings()
+type ScanTimings struct {
+	Scan     time.Duration //Time spent in sql.Rows.Scan()
+	Pointers time.Duration //Time spent resolving struct pointers
+	Convert  time.Duration //Time spent converting the column data into the members
+	NumScans int           //Number of successful sql.Rows.Scan() calls
+}
+
+// Timings returns the accumulated phase timings of the RowReader’s scans. This is only recorded when compiled with the gofastersql_debug build tag, and otherwise always returns an empty ScanTimings.
+func (rr *RowReader) Timings() ScanTimings {
+	return rr.timings.get()
+}
+
+// ResetTimings clears the accumulated phase timings of the RowReader. See RowReader.Timings()
+func (rr *RowReader) ResetTimings() {
+	rr.timings.reset()
+}
+
+// markAllNullPointers fills in allNull with which nilOnNull struct pointers have only NULL columns (recursively)
+func (rr *RowReader) markAllNullPointers() {
+	for i, p := range rr.sm.pointers {
+		rr.allNull[i+1] = p.nilOnNull
+	}
+	for i, sf := range rr.sm.fields {
+		if rr.rawBytesArr[i] == nil {
+			continue
+		}
+		for ptrIndex := sf.pointerIndex; ptrIndex != 0; ptrIndex = rr.sm.pointers[ptrIndex-1].parentIndex {
+			rr.allNull[ptrIndex] = false
+		}
+	}
+}
+
+// setPresenceMembers sets the presence members of structures to if any of the structure’s columns are not NULL (recursively)
+func (rr *RowReader) setPresenceMembers() {
+	for i := range rr.present {
+		rr.present[i] = false
+	}
+	for i, sf := range rr.sm.fields {
+		if rr.rawBytesArr[i] == nil {
+			continue
+		}
+		for presIndex := sf.presenceIndex; presIndex != 0 && !rr.present[presIndex]; presIndex = rr.sm.presences[presIndex-1].parentIndex {
+			rr.present[presIndex] = true
+		}
+	}
+	for i, p := range rr.sm.presences {
+		if parent := rr.pointers[p.pointerIndex]; parent != nil {
+			*(*bool)(unsafe.Add(parent, p.offset)) = rr.present[i+1]
+		}
+	}
 }
 
 //------------Row Close/Next functions overwritten during benchmarks------------
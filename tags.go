@@ -0,0 +1,831 @@
+//Struct tag options that modify how members are modeled and converted
+
+package gofastersql
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// fieldTag is a single option from a member’s “gfs” struct tag in the format “name[:arg]”
+type fieldTag struct {
+	name, arg string
+}
+
+// fieldTags is the list of options in a member’s “gfs” struct tag (comma separated), in the order they were given
+type fieldTags []fieldTag
+
+// tagOptionFunc modifies (or replaces) the conversion function for a member of type t. fn is nil if there is no default conversion function for the type.
+type tagOptionFunc func(t reflect.Type, arg string, fn converterFunc) (converterFunc, error)
+
+var tagOptions = map[string]tagOptionFunc{
+	"json":         tagJSON,
+	"lower":        tagStringCase(strings.ToLower),
+	"upper":        tagStringCase(strings.ToUpper),
+	"decimal":      tagDecimal,
+	"onoverflow":   tagOnOverflow,
+	"base":         tagBase,
+	"maxlen":       tagMaxLen,
+	"jsonvalidate": tagJSONValidate,
+	"autobin":      tagAutoBin,
+	"stream":       tagStream,
+	"enum":         tagEnum,
+	"enumdefault":  tagEnumDefault,
+	"csv":          tagCSV,
+	"bits":         tagBits,
+	"kv":           tagKV,
+	"datetime2col": tagDateTime2Col,
+	"concrete":     tagConcrete,
+	"pginterval":   tagPGInterval,
+	"duration":     tagDuration,
+	"copyof":       tagCopyOf,
+}
+
+// memberTagOptionFunc is a tagOptionFunc that also needs to know about the member (fld) and the structure that contains it (parent)
+type memberTagOptionFunc func(parent reflect.Type, fld reflect.StructField, t reflect.Type, arg string, fn converterFunc) (converterFunc, error)
+
+var memberTagOptions = map[string]memberTagOptionFunc{
+	"resolve": tagResolve,
+}
+
+// parseTags extracts the “gfs” options (and the “json” option of the “db” tag) from a struct tag
+func parseTags(tag reflect.StructTag) fieldTags {
+	var ret fieldTags
+	if s := tag.Get("gfs"); len(s) != 0 {
+		for _, opt := range strings.Split(s, ",") {
+			name, arg, _ := strings.Cut(opt, ":")
+			ret = append(ret, fieldTag{strings.TrimSpace(name), arg})
+		}
+	}
+
+	//A “json” option in the db tag (ex: db:",json") is the same as gfs:"json". Other db tag options are ignored.
+	if _, dbOpts, _ := strings.Cut(tag.Get("db"), ","); len(dbOpts) != 0 && !ret.has("json") {
+		for _, opt := range strings.Split(dbOpts, ",") {
+			if strings.TrimSpace(opt) == "json" {
+				ret = append(fieldTags{{"json", ""}}, ret...)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// dbTagName returns the name in a member’s db tag (ex: db:"full_name"), or otherwise its Go name. See ReaderOptions.DBTagNames
+func dbTagName(fld reflect.StructField) string {
+	if name, _, _ := strings.Cut(fld.Tag.Get("db"), ","); len(name) != 0 {
+		return name
+	}
+	return fld.Name
+}
+
+// isSkippedField returns if a member is ignored by the model. This is the case for members tagged with gfs:"-" or db:"-", and for members of func type (ex: computed values filled in after scanning).
+func isSkippedField(fld reflect.StructField) bool {
+	return fld.Type.Kind() == reflect.Func || fld.Tag.Get("gfs") == "-" || fld.Tag.Get("db") == "-"
+}
+
+// has returns if the option is in the list
+func (ft fieldTags) has(name string) bool {
+	for _, t := range ft {
+		if t.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isCollapsed returns if a struct member is converted as a single column (or is a copy of another member’s column) instead of being recursed into
+func (ft fieldTags) isCollapsed() bool {
+	return ft.has("json") || ft.has("copyof")
+}
+
+// needsParent returns if an option’s conversion function accesses other members of the structure that contains the member (see memberTagOptions)
+func (ft fieldTags) needsParent() bool {
+	for _, t := range ft {
+		if memberTagOptions[t.name] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// numColumns returns the number of columns a member consumes. Copies (gfs:"copyof") share the column of the member they copy.
+func (ft fieldTags) numColumns() int {
+	return cond(ft.has("copyof"), 0, cond(ft.has("datetime2col"), 2, 1))
+}
+
+// copyOf returns the name of the member that a member is a copy of (gfs:"copyof"), or "" if it is not a copy
+func (ft fieldTags) copyOf() string {
+	for _, t := range ft {
+		if t.name == "copyof" {
+			return t.arg
+		}
+	}
+	return ""
+}
+
+// structOptions returns the tag options for a member that is a recursed structure. presentName is the name of its presence member.
+func (ft fieldTags) structOptions(isPointer bool) (nilOnNull bool, presentName string, err error) {
+	for _, tag := range ft {
+		switch tag.name {
+		case "nilonnull":
+			if !isPointer {
+				return false, "", errors.New("gfs tag option “nilonnull” requires a structure pointer")
+			}
+			nilOnNull = true
+		case "present":
+			if len(tag.arg) == 0 {
+				return false, "", errors.New("gfs tag option “present” requires a member name")
+			}
+			presentName = tag.arg
+		default:
+			return false, "", fmt.Errorf("Unknown gfs tag option for a structure “%s”", tag.name)
+		}
+	}
+	return
+}
+
+// apply runs all the tag options on the conversion function, in order. parent is the structure that contains fld.
+func (ft fieldTags) apply(parent reflect.Type, fld reflect.StructField, t reflect.Type, fn converterFunc) (converterFunc, error) {
+	for i, tag := range ft {
+		//gfs:"base" replaces the conversion function, so it would discard the options before it
+		if tag.name == "base" && i != 0 {
+			return nil, errors.New("gfs tag option “base” must be the first option")
+		}
+
+		optFunc := tagOptions[tag.name]
+		if memberOptFunc := memberTagOptions[tag.name]; memberOptFunc != nil {
+			optFunc = func(t reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+				return memberOptFunc(parent, fld, t, arg, fn)
+			}
+		} else if optFunc == nil {
+			return nil, fmt.Errorf("Unknown gfs tag option “%s”", tag.name)
+		}
+
+		var err error
+		if fn, err = optFunc(t, tag.arg, fn); err != nil {
+			return nil, fmt.Errorf("gfs tag option “%s”: %s", tag.name, err.Error())
+		}
+	}
+	return fn, nil
+}
+
+//---------------------------------Tag options----------------------------------
+
+// tagJSON decodes the column via json.Unmarshal. The member is set to its zero value first (and left that way on NULL).
+func tagJSON(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	return func(in []byte, p upt) error {
+		v := reflect.NewAt(t, unsafe.Pointer(p))
+		v.Elem().Set(reflect.Zero(t))
+		if in == nil {
+			return nil
+		}
+		return json.Unmarshal(in, v.Interface())
+	}, nil
+}
+
+// tagStringCase normalizes the case of a string member after conversion. strings.ToLower/ToUpper do not allocate if the string is already normalized.
+func tagStringCase(toCase func(string) string) tagOptionFunc {
+	return func(t reflect.Type, _ string, fn converterFunc) (converterFunc, error) {
+		if t.Kind() != reflect.String {
+			return nil, errors.New("Member must be a string")
+		}
+		return func(in []byte, p upt) error {
+			if err := fn(in, p); err != nil {
+				return err
+			}
+			s := (*string)(p)
+			*s = toCase(*s)
+			return nil
+		}, nil
+	}
+}
+
+// tagDecimal confirms a string member receives a valid decimal number (optional sign, digits, and an optional fractional part). NULL is not validated.
+func tagDecimal(t reflect.Type, _ string, fn converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.String {
+		return nil, errors.New("Member must be a string")
+	}
+	return func(in []byte, p upt) error {
+		if in != nil && !isDecimal(in) {
+			return fmt.Errorf("Invalid decimal “%s”", in)
+		}
+		return fn(in, p)
+	}, nil
+}
+
+// tagOnOverflow determines what happens when an integer member receives a number outside of its range. The argument is “error” (the default without the tag), “clamp” (set to the closest value in range), or “skip” (leave the prior value).
+func tagOnOverflow(t reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+	var isSigned bool
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		isSigned = true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, errors.New("Member must be an integer")
+	}
+
+	switch arg {
+	case "error":
+		return fn, nil
+	case "clamp", "skip":
+	default:
+		return nil, fmt.Errorf("Unknown overflow mode “%s”", arg)
+	}
+	isClamp, bits := arg == "clamp", t.Bits()
+
+	return func(in []byte, p upt) error {
+		err := fn(in, p)
+		var numErr *strconv.NumError
+		if err == nil || !errors.As(err, &numErr) || numErr.Err != strconv.ErrRange {
+			return err
+		} else if !isClamp {
+			return nil
+		}
+
+		v := reflect.NewAt(t, unsafe.Pointer(p)).Elem()
+		if !isSigned {
+			v.SetUint(^uint64(0) >> (64 - bits))
+		} else if bytes.HasPrefix(numText(in), []byte{'-'}) {
+			v.SetInt(-1 << (bits - 1))
+		} else {
+			v.SetInt(1<<(bits-1) - 1)
+		}
+		return nil
+	}, nil
+}
+
+// tagBase parses an integer or nulltypes integer member in the base given in the argument (2 to 36, ex: 16 for hexadecimal columns), which takes precedence over ReaderOptions.DefaultIntBase
+func tagBase(t reflect.Type, arg string, _ converterFunc) (converterFunc, error) {
+	if _, sff := scalarToConversionFunc(t); sff&sffIsInteger == 0 {
+		return nil, errors.New("Member must be an integer")
+	}
+	base, err := strconv.ParseUint(arg, 10, 8)
+	if err != nil || base < 2 || base > 36 {
+		return nil, fmt.Errorf("Invalid base “%s”", arg)
+	}
+	return intConverterForType(t, int(base)), nil
+}
+
+// tagMaxLen limits the length (in bytes) of a string or byte slice member. The argument is “LENGTH[:MODE]” where MODE is “truncate” (the default) or “error”. Truncation does not split the UTF-8 characters of strings, while byte slices are cut at exactly LENGTH.
+func tagMaxLen(t reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.String && !isByteSlice(t) {
+		return nil, errors.New("Member must be a byte slice or string")
+	}
+	lenStr, mode, _ := strings.Cut(arg, ":")
+	maxLen, err := strconv.ParseUint(lenStr, 10, 31)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid length “%s”", lenStr)
+	}
+	switch mode {
+	case "", "truncate", "error":
+	default:
+		return nil, fmt.Errorf("Unknown mode “%s”", mode)
+	}
+	isError, isString, max := mode == "error", t.Kind() == reflect.String, int(maxLen)
+
+	return func(in []byte, p upt) error {
+		if len(in) > max {
+			if isError {
+				return fmt.Errorf("Length %d is greater than the maximum of %d", len(in), max)
+			}
+			cut := max
+			for isString && cut > 0 && !utf8.RuneStart(in[cut]) {
+				cut--
+			}
+			in = in[:cut]
+		}
+		return fn(in, p)
+	}, nil
+}
+
+// tagJSONValidate confirms a byte slice (ex: json.RawMessage) or string member receives well-formed JSON. NULL is not validated.
+func tagJSONValidate(t reflect.Type, _ string, fn converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.String && !isByteSlice(t) {
+		return nil, errors.New("Member must be a byte slice or string")
+	}
+	return func(in []byte, p upt) error {
+		if in != nil && !json.Valid(in) {
+			return errors.New("Invalid JSON")
+		}
+		return fn(in, p)
+	}, nil
+}
+
+// tagAutoBin hex decodes a byte slice member when the column is a hexadecimal literal starting with “0x” (ex: MySQL’s --binary-as-hex). Other columns are copied as is.
+func tagAutoBin(t reflect.Type, _ string, fn converterFunc) (converterFunc, error) {
+	if !isByteSlice(t) {
+		return nil, errors.New("Member must be a byte slice")
+	}
+	return func(in []byte, p upt) error {
+		if len(in) < 2 || in[0] != '0' || (in[1] != 'x' && in[1] != 'X') {
+			return fn(in, p)
+		}
+		decoded := make([]byte, hex.DecodedLen(len(in)-2))
+		if _, err := hex.Decode(decoded, in[2:]); err != nil {
+			return fmt.Errorf("Invalid hexadecimal literal: %s", err.Error())
+		}
+		return fn(decoded, p)
+	}, nil
+}
+
+// tagStream sets an io.Reader member to a reader over the column’s RawBytes (which are only valid until the next scan) instead of a copy. NULL sets the member to nil.
+func tagStream(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	if t != lookupType.reader {
+		return nil, errors.New("Member must be an io.Reader")
+	}
+	return convStream, nil
+}
+func convStream(in []byte, p upt) error {
+	if in == nil {
+		*(*io.Reader)(p) = nil
+	} else {
+		*(*io.Reader)(p) = bytes.NewReader(in)
+	}
+	return nil
+}
+
+// convStreamCopy is convStream over a copy of the column, used by the singular ScanRow functions as the RawBytes are invalid after the rows are closed
+func convStreamCopy(in []byte, p upt) error {
+	if in != nil {
+		in = append([]byte(nil), in...)
+	}
+	return convStream(in, p)
+}
+
+// isDecimal returns if the text is in the format [+-]digits[.digits] (at least 1 digit is required on either side of the dot)
+func isDecimal(in []byte) bool {
+	if len(in) != 0 && (in[0] == '-' || in[0] == '+') {
+		in = in[1:]
+	}
+
+	numDigits, hasDot := 0, false
+	for _, c := range in {
+		if c >= '0' && c <= '9' {
+			numDigits++
+		} else if c == '.' && !hasDot {
+			hasDot = true
+		} else {
+			return false
+		}
+	}
+	return numDigits != 0
+}
+
+// tagEnum confirms a string member receives one of the listed values (separated by “|”). NULL is not validated.
+func tagEnum(t reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.String {
+		return nil, errors.New("Member must be a string")
+	}
+	values := make(map[string]struct{})
+	for _, v := range strings.Split(arg, "|") {
+		values[v] = struct{}{}
+	}
+	return func(in []byte, p upt) error {
+		if in != nil {
+			if _, ok := values[b2s(in)]; !ok {
+				return unknownEnumError(string(in))
+			}
+		}
+		return fn(in, p)
+	}, nil
+}
+
+// unknownEnumError is returned by tagEnum when a value is not in its list
+type unknownEnumError string
+
+func (e unknownEnumError) Error() string {
+	return fmt.Sprintf("Unknown enum value “%s”", string(e))
+}
+
+// tagEnumDefault sets a string member to the given default when the column is NULL, or when the value is not found in a preceding enum option
+func tagEnumDefault(t reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.String {
+		return nil, errors.New("Member must be a string")
+	}
+	return func(in []byte, p upt) error {
+		if in == nil {
+			*(*string)(p) = arg
+			return nil
+		}
+		err := fn(in, p)
+		if _, ok := err.(unknownEnumError); ok {
+			*(*string)(p) = arg
+			return nil
+		}
+		return err
+	}, nil
+}
+
+// tagCSV splits the column into the elements of a slice member by a separator (the argument, which defaults to “,”).
+// Surrounding braces are removed (ex: Postgres arrays “{1,2,3}”) and “NULL” elements are passed to the element converter as NULL (ex: []nulltypes.NullInt64 elements become IsNull).
+// NULL sets the slice to nil and an empty column sets it to an empty slice. Quoted elements are not supported.
+func tagCSV(t reflect.Type, arg string, _ converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.Slice {
+		return nil, errors.New("Member must be a slice")
+	}
+	elemType := t.Elem()
+	elemFn, elemFlags := scalarToConversionFunc(elemType)
+	if elemFn == nil || elemFlags&sffIsRawBytes != 0 {
+		return nil, fmt.Errorf("Unsupported slice element type %s", elemType.String())
+	}
+	sep := []byte(cond(len(arg) == 0, ",", arg))
+	elemSize := elemType.Size()
+
+	return func(in []byte, p upt) error {
+		sl := reflect.NewAt(t, unsafe.Pointer(p)).Elem()
+		if in == nil {
+			sl.Set(reflect.Zero(t))
+			return nil
+		}
+		if len(in) >= 2 && in[0] == '{' && in[len(in)-1] == '}' {
+			in = in[1 : len(in)-1]
+		}
+		if len(in) == 0 {
+			sl.Set(reflect.MakeSlice(t, 0, 0))
+			return nil
+		}
+
+		parts := bytes.Split(in, sep)
+		out := reflect.MakeSlice(t, len(parts), len(parts))
+		outPointer := out.UnsafePointer()
+		for i, part := range parts {
+			if b2s(part) == "NULL" {
+				part = nil
+			}
+			if err := elemFn(part, upt(unsafe.Add(outPointer, uintptr(i)*elemSize))); err != nil {
+				return fmt.Errorf("Element #%d: %s", i, err.Error())
+			}
+		}
+		sl.Set(out)
+		return nil
+	}, nil
+}
+
+// tagBits converts a string of bits (ex: “10110”) into a []bool member, one element per character ('1' is true and '0' is false). NULL sets the slice to nil and an empty column sets it to an empty slice.
+func tagBits(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Bool {
+		return nil, errors.New("Member must be a bool slice")
+	}
+	return func(in []byte, p upt) error {
+		out := (*[]bool)(p)
+		if in == nil {
+			*out = nil
+			return nil
+		}
+
+		bits := make([]bool, len(in))
+		for i, c := range in {
+			switch c {
+			case '1':
+				bits[i] = true
+			case '0':
+			default:
+				return fmt.Errorf("Invalid bit “%c” at position %d", c, i)
+			}
+		}
+		*out = bits
+		return nil
+	}, nil
+}
+
+// tagKV parses “key=value” pairs (ex: “a=1;b=2”) into a map[string]string member. The argument is “PAIR_SEPARATOR:KEY_VALUE_SEPARATOR”, which defaults to “;:=”.
+// Empty pairs are ignored. NULL sets the map to nil and an empty column sets it to an empty map.
+func tagKV(t reflect.Type, arg string, _ converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String || t.Elem().Kind() != reflect.String {
+		return nil, errors.New("Member must be a map of strings to strings")
+	}
+	pairSep, kvSep, _ := strings.Cut(arg, ":")
+	pairSep, kvSep = cond(len(pairSep) == 0, ";", pairSep), cond(len(kvSep) == 0, "=", kvSep)
+
+	return func(in []byte, p upt) error {
+		m := reflect.NewAt(t, unsafe.Pointer(p)).Elem()
+		if in == nil {
+			m.Set(reflect.Zero(t))
+			return nil
+		}
+
+		pairs := strings.Split(string(in), pairSep)
+		out := reflect.MakeMapWithSize(t, len(pairs))
+		for i, pair := range pairs {
+			if len(pair) == 0 {
+				continue
+			}
+			key, val, found := strings.Cut(pair, kvSep)
+			if !found {
+				return fmt.Errorf("Pair #%d is missing “%s”", i, kvSep)
+			}
+			out.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), reflect.ValueOf(val).Convert(t.Elem()))
+		}
+		m.Set(out)
+		return nil
+	}, nil
+}
+
+// durationUnits are the units of the “duration” gfs tag option
+var durationUnits = map[string]time.Duration{"ns": time.Nanosecond, "us": time.Microsecond, "ms": time.Millisecond, "s": time.Second, "m": time.Minute, "h": time.Hour}
+
+// tagDuration sets the unit of integer columns for a time.Duration member (ex: “s” for seconds), or with “string”, parses Go duration strings (ex: “1h30m”, see time.ParseDuration). NULL sets the member to 0.
+func tagDuration(t reflect.Type, arg string, _ converterFunc) (converterFunc, error) {
+	if t != lookupType.duration {
+		return nil, errors.New("Member must be a time.Duration")
+	}
+	if arg == "string" {
+		return func(in []byte, p upt) error {
+			d := (*time.Duration)(p)
+			if in == nil {
+				*d = 0
+				return nil
+			}
+			parsed, err := time.ParseDuration(b2s(in))
+			if err != nil {
+				return err
+			}
+			*d = parsed
+			return nil
+		}, nil
+	}
+
+	unit, ok := durationUnits[arg]
+	if !ok {
+		return nil, fmt.Errorf("Unknown unit “%s” (must be ns, us, ms, s, m, h, or string)", arg)
+	}
+	return func(in []byte, p upt) error {
+		if err := convDuration(in, p); err != nil {
+			return err
+		}
+		d := (*time.Duration)(p)
+		if *d > math.MaxInt64/unit || *d < math.MinInt64/unit {
+			return fmt.Errorf("Duration “%s%s” is out of range", in, arg)
+		}
+		*d *= unit
+		return nil
+	}, nil
+}
+
+// tagPGInterval converts Postgres interval text (ex: “01:30:00”, “1 day 02:03:04”, “-2 days +03:00:00”) into a time.Duration member. Days are 24 hours. Months and years have no fixed duration so they return an error. NULL sets the member to 0.
+func tagPGInterval(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	if t != lookupType.duration {
+		return nil, errors.New("Member must be a time.Duration")
+	}
+	return convPGInterval, nil
+}
+func convPGInterval(in []byte, p upt) error {
+	d := (*time.Duration)(p)
+	*d = 0
+	if in == nil {
+		return nil
+	}
+
+	parts := strings.Fields(b2s(in))
+	if len(parts) == 0 {
+		return errors.New("Empty interval")
+	}
+	var total time.Duration
+	for i := 0; i < len(parts); i++ {
+		//Times are in the format [+-]HH:MM[:SS[.FRACTION]]
+		if strings.Contains(parts[i], ":") {
+			tm, err := parsePGIntervalTime(parts[i])
+			if err != nil {
+				return fmt.Errorf("Invalid interval “%s”: %s", in, err.Error())
+			}
+			total += tm
+			continue
+		}
+
+		//Everything else is a number followed by its unit
+		if i+1 == len(parts) {
+			return fmt.Errorf("Invalid interval “%s”: Missing unit for “%s”", in, parts[i])
+		}
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid interval “%s”: %s", in, err.Error())
+		}
+		i++
+		switch parts[i] {
+		case "day", "days":
+			const maxDays = int64(math.MaxInt64 / (24 * time.Hour))
+			if n > maxDays || n < -maxDays {
+				return fmt.Errorf("Invalid interval “%s”: Out of range", in)
+			}
+			total += time.Duration(n) * 24 * time.Hour
+		case "mon", "mons", "year", "years":
+			return fmt.Errorf("Invalid interval “%s”: Months and years cannot be converted to a duration", in)
+		default:
+			return fmt.Errorf("Invalid interval “%s”: Unknown unit “%s”", in, parts[i])
+		}
+	}
+
+	*d = total
+	return nil
+}
+
+// parsePGIntervalTime parses the time part of a Postgres interval ([+-]HH:MM[:SS[.FRACTION]]). Hours are not limited to a single day.
+func parsePGIntervalTime(s string) (time.Duration, error) {
+	sign := ""
+	if len(s) != 0 && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[0:1], s[1:]
+	}
+	timeParts := strings.Split(s, ":")
+	if len(timeParts) < 2 || len(timeParts) > 3 {
+		return 0, errors.New("Invalid time")
+	}
+	for i, part := range timeParts {
+		if len(part) == 0 || (i != 2 && !isDigits(part)) || (i == 2 && !isDecimal([]byte(part))) || part[0] == '-' || part[0] == '+' {
+			return 0, errors.New("Invalid time")
+		}
+	}
+	if len(timeParts) == 2 {
+		timeParts = append(timeParts, "0")
+	}
+	return time.ParseDuration(sign + timeParts[0] + "h" + timeParts[1] + "m" + timeParts[2] + "s")
+}
+
+// isDigits returns if the string only contains ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// tagDateTime2Col converts a DATE column into the date of a time.Time member, keeping its time of day. The member consumes a second (TIME) column that sets its time of day via convTimeOfDay. See fieldTags.numColumns()
+// As the 2 columns can be converted in either order (ex: via a RowReaderNamed), each only replaces its own part of the member.
+func tagDateTime2Col(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	if t != lookupType.time {
+		return nil, errors.New("Member must be a time.Time")
+	}
+	return func(in []byte, p upt) error {
+		tm := (*time.Time)(p)
+		clock := tm.Sub(dayStart(*tm))
+		if in == nil || isZeroDate(in) {
+			if err := convTime(in, p); err != nil {
+				return err
+			}
+		} else if d, err := time.Parse(`2006-01-02`, b2s(in)); err != nil {
+			return err
+		} else {
+			*tm = d
+		}
+		*tm = tm.Add(clock)
+		return nil
+	}, nil
+}
+
+// convTimeOfDay converts a TIME column (“HH:MM:SS[.fraction]”, within a single day) into the time of day of a time.Time member, keeping its date. NULL sets the time of day to midnight.
+func convTimeOfDay(in []byte, p upt) error {
+	tm := (*time.Time)(p)
+	*tm = dayStart(*tm)
+	if in == nil {
+		return nil
+	}
+
+	parts := strings.Split(b2s(in), ":")
+	if len(parts) != 3 {
+		return fmt.Errorf("Invalid time “%s”", in)
+	}
+	hours, err1 := strconv.ParseUint(parts[0], 10, 8)
+	mins, err2 := strconv.ParseUint(parts[1], 10, 8)
+	secs, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil || hours >= 24 || mins >= 60 || secs < 0 || secs >= 60 {
+		return fmt.Errorf("Invalid time “%s”", in)
+	}
+
+	*tm = tm.Add(time.Duration(hours)*time.Hour + time.Duration(mins)*time.Minute + time.Duration(math.Round(secs*1e9)))
+	return nil
+}
+
+// dayStart returns midnight of the time’s date
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// tagCopyOf marks a member as a copy of the column of another member (“MEMBER”) of the same structure. The member consumes no column of its own and keeps its conversion function, which is run after the copied member’s (see createStructModelFromStruct).
+func tagCopyOf(_ reflect.Type, arg string, fn converterFunc) (converterFunc, error) {
+	if len(arg) == 0 {
+		return nil, errors.New("Requires a member name")
+	}
+	return fn, nil
+}
+
+// TypeResolver returns a pointer to a new value of the concrete type that an interface member’s column is scanned into, based on the value of its discriminator member. See RegisterTypeResolver
+type TypeResolver func(discriminator string) (any, error)
+
+var typeResolvers = make(map[string]TypeResolver)
+var typeResolversLock sync.RWMutex
+
+/*
+RegisterTypeResolver registers a TypeResolver for interface members tagged with gfs:"resolve:NAME:DISCRIMINATOR", where DISCRIMINATOR is the name of a string member in the same structure.
+
+For each row, the resolver is called with the discriminator member’s value and the column is converted into the value it returns (via the standard converter for its type, or via json.Unmarshal for structures), which is then stored in the interface member.
+The discriminator member’s column must come before the interface member’s column. NULL sets the interface member to nil.
+
+Registering a name again replaces its resolver, though models that were already created keep the resolver they found.
+*/
+func RegisterTypeResolver(name string, fn TypeResolver) {
+	typeResolversLock.Lock()
+	typeResolvers[name] = fn
+	typeResolversLock.Unlock()
+}
+
+// tagResolve converts the column into an interface member via a TypeResolver. The argument is “RESOLVER_NAME:DISCRIMINATOR_MEMBER”
+func tagResolve(parent reflect.Type, fld reflect.StructField, t reflect.Type, arg string, _ converterFunc) (converterFunc, error) {
+	//Confirm the member and its discriminator
+	if t.Kind() != reflect.Interface || fld.Type.Kind() == reflect.Pointer {
+		return nil, errors.New("Member must be an interface")
+	}
+	name, discName, _ := strings.Cut(arg, ":")
+	typeResolversLock.RLock()
+	resolver := typeResolvers[name]
+	typeResolversLock.RUnlock()
+	if resolver == nil {
+		return nil, fmt.Errorf("Type resolver “%s” is not registered", name)
+	}
+	disc, ok := parent.FieldByName(discName)
+	if !ok || len(disc.Index) != 1 || disc.Type.Kind() != reflect.String {
+		return nil, fmt.Errorf("Discriminator “%s” must be a string member of the same structure", discName)
+	}
+	discOffset := int(disc.Offset) - int(fld.Offset)
+
+	var converters concreteConverters
+	return func(in []byte, p upt) error {
+		member := reflect.NewAt(t, unsafe.Pointer(p)).Elem()
+		if in == nil {
+			member.Set(reflect.Zero(t))
+			return nil
+		}
+
+		//Get the concrete value from the resolver
+		v, err := resolver(*(*string)(unsafe.Add(unsafe.Pointer(p), discOffset)))
+		if err != nil {
+			return err
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return errors.New("Type resolver must return a non-nil pointer")
+		} else if !rv.Type().Implements(t) && !rv.Type().Elem().Implements(t) {
+			return fmt.Errorf("Type resolver returned %s which does not implement %s", rv.Type().String(), t.String())
+		}
+
+		//Convert into the concrete value and store it in the member
+		if err := converters.get(rv.Type().Elem())(in, upt(rv.UnsafePointer())); err != nil {
+			return err
+		}
+		if rv.Type().Implements(t) {
+			member.Set(rv)
+		} else {
+			member.Set(rv.Elem())
+		}
+		return nil
+	}, nil
+}
+
+// concreteConverters caches the converters of the concrete types that interface members are converted into. Structures (and other types without a standard converter) are converted via json.Unmarshal.
+type concreteConverters struct {
+	sync.Map
+}
+
+func (cc *concreteConverters) get(concrete reflect.Type) converterFunc {
+	if fn, ok := cc.Load(concrete); ok {
+		return fn.(converterFunc)
+	}
+	fn, sff := scalarToConversionFunc(concrete)
+	if fn == nil || sff&sffIsRawBytes != 0 {
+		fn, _ = tagJSON(concrete, "", nil)
+	}
+	cc.Store(concrete, fn)
+	return fn
+}
+
+/*
+tagConcrete converts the column into the value that an interface member points to, using the concrete type that the member holds at scan time.
+Since the number of columns is fixed when the model is created, the concrete value is always converted from the single column (structures via json.Unmarshal).
+*/
+func tagConcrete(t reflect.Type, _ string, _ converterFunc) (converterFunc, error) {
+	if t.Kind() != reflect.Interface {
+		return nil, errors.New("Member must be an interface")
+	}
+
+	var converters concreteConverters
+	return func(in []byte, p upt) error {
+		rv := reflect.NewAt(t, unsafe.Pointer(p)).Elem().Elem()
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return ErrPointerNotInitialized
+		}
+		return converters.get(rv.Type().Elem())(in, upt(rv.UnsafePointer()))
+	}, nil
+}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	gf "github.com/dakusan/gofastersql"
 	"github.com/dakusan/gofastersql/nulltypes"
 	_ "github.com/go-sql-driver/mysql"
+	"io"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unsafe"
 )
 
 //goland:noinspection ALL
@@ -523,6 +527,122 @@ func TestNulls(t *testing.T) {
 			t.Fatal("Nulled scalar marshal #2 did not match: " + tsnToString())
 		}
 	})
+
+	//Run test for pointers to nullable scalar types
+	t.Run("Null scalar pointers", func(t *testing.T) {
+		type TestStructNullPointers struct {
+			U8  *nulltypes.NullUint8
+			U16 *nulltypes.NullUint16
+			U32 *nulltypes.NullUint32
+			U64 *nulltypes.NullUint64
+			I8  *nulltypes.NullInt8
+			I16 *nulltypes.NullInt16
+			I32 *nulltypes.NullInt32
+			I64 *nulltypes.NullInt64
+			F32 *nulltypes.NullFloat32
+			F64 *nulltypes.NullFloat64
+			S   *nulltypes.NullString
+			BA  *nulltypes.NullByteArray
+			RB  *nulltypes.NullRawBytes
+			B   *nulltypes.NullBool
+			T   *nulltypes.NullTime
+		}
+		tsnp := TestStructNullPointers{
+			new(nulltypes.NullUint8), new(nulltypes.NullUint16), new(nulltypes.NullUint32), new(nulltypes.NullUint64),
+			new(nulltypes.NullInt8), new(nulltypes.NullInt16), new(nulltypes.NullInt32), new(nulltypes.NullInt64),
+			new(nulltypes.NullFloat32), new(nulltypes.NullFloat64), new(nulltypes.NullString), new(nulltypes.NullByteArray),
+			new(nulltypes.NullRawBytes), new(nulltypes.NullBool), new(nulltypes.NullTime),
+		}
+		tsnpToString := func() string {
+			list := []fmt.Stringer{tsnp.U8, tsnp.U16, tsnp.U32, tsnp.U64, tsnp.I8, tsnp.I16, tsnp.I32, tsnp.I64, tsnp.F32, tsnp.F64, tsnp.S, tsnp.BA, tsnp.RB, tsnp.B, tsnp.T}
+			s := make([]string, len(list))
+			for i, v := range list {
+				s[i] = v.String()
+			}
+			return strings.Join(s, ",")
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT i1+1, i2, i1+2, i2, i1+3, i2, i1+4, i2, i1+5, i2, i1+6, 'ba', 'rb', i2, '2001-02-03 05:06:07.21' FROM goTest2`)), &tsnp)))
+		if tsnpToString() != `6,NULL,7,NULL,8,NULL,9,NULL,10,NULL,11,ba,rb,NULL,2001-02-03 05:06:07.21` {
+			t.Fatal("Nulled scalar pointer marshal did not match: " + tsnpToString())
+		}
+
+		//The RawBytes must have been copied in single row mode, so it must survive the next scan
+		rb := tsnp.RB.Val
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT i2, i1+11, i2, i1+12, i2, i1+13, i2, i1+14, i2, i1+15, i2, i2, i2, i1+17, i2 FROM goTest2`)), &tsnp)))
+		if tsnpToString() != `NULL,16,NULL,17,NULL,18,NULL,19,NULL,20,NULL,NULL,NULL,false,NULL` {
+			t.Fatal("Nulled scalar pointer marshal #2 did not match: " + tsnpToString())
+		} else if string(rb) != "rb" {
+			t.Fatal("RawBytes changed when it should have stayed the same: " + string(rb))
+		}
+
+		//Uninitialized pointers
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT i2, i2, i2, i2, i2, i2, i2, i2, i2, i2, i2, i2, i2, i2, i2 FROM goTest2`)), &TestStructNullPointers{}); err == nil {
+			t.Fatal("Expected errors not found")
+		} else if err.Error() != strings.Join([]string{
+			`Error on U8: Pointer not initialized`, `Error on U16: Pointer not initialized`, `Error on U32: Pointer not initialized`,
+			`Error on U64: Pointer not initialized`, `Error on I8: Pointer not initialized`, `Error on I16: Pointer not initialized`,
+			`Error on I32: Pointer not initialized`, `Error on I64: Pointer not initialized`, `Error on F32: Pointer not initialized`,
+			`Error on F64: Pointer not initialized`, `Error on S: Pointer not initialized`, `Error on BA: Pointer not initialized`,
+			`Error on RB: Pointer not initialized`, `Error on B: Pointer not initialized`, `Error on T: Pointer not initialized`,
+		}, "\n") {
+			t.Fatal("Expected errors not correct:\n" + err.Error())
+		}
+	})
+
+	//Run test for nullable scalar types inside structure pointers nested 2 levels deep
+	t.Run("Null scalars in nested struct pointers", func(t *testing.T) {
+		type TestStructNullLevel2 struct {
+			I64 nulltypes.NullInt64
+			S   nulltypes.NullString
+		}
+		type TestStructNullLevel1 struct {
+			I8  int8
+			L2  *TestStructNullLevel2
+			F64 nulltypes.NullFloat64
+		}
+		type TestStructNullNested struct {
+			I  int
+			L1 *TestStructNullLevel1
+		}
+		tsnn := TestStructNullNested{L1: &TestStructNullLevel1{L2: new(TestStructNullLevel2)}}
+		tsnnToString := func() string {
+			return fmt.Sprintf("%d,%d,%s,%s,%s", tsnn.I, tsnn.L1.I8, tsnn.L1.L2.I64.String(), tsnn.L1.L2.S.String(), tsnn.L1.F64.String())
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT i1, i1+1, i1+2, 'str', 1.5 FROM goTest2`)), &tsnn)))
+		if tsnnToString() != `5,6,7,str,1.5` {
+			t.Fatal("Nested null scalar marshal did not match: " + tsnnToString())
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT i1, i1+2, i2, i2, i2 FROM goTest2`)), &tsnn)))
+		if tsnnToString() != `5,7,NULL,NULL,NULL` {
+			t.Fatal("Nested null scalar marshal #2 did not match: " + tsnnToString())
+		}
+	})
+
+	//Run test for embedded (anonymous) nullable scalar types, which are matched by their type names
+	t.Run("Embedded null scalars", func(t *testing.T) {
+		type TestStructNullEmbedded struct {
+			I int
+			nulltypes.NullTime
+			nulltypes.NullInt64
+		}
+		var tsne TestStructNullEmbedded
+		tsneToString := func() string {
+			return fmt.Sprintf("%d,%s,%s", tsne.I, tsne.NullTime.String(), tsne.NullInt64.String())
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT i1 AS NullInt64, '2001-02-03 05:06:07' AS NullTime, i1+1 AS I FROM goTest2`)), &tsne)))
+		if tsneToString() != `6,2001-02-03 05:06:07,5` {
+			t.Fatal("Embedded null scalar marshal did not match: " + tsneToString())
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT i2 AS NullTime, i1+2 AS I, i2 AS NullInt64 FROM goTest2`)), &tsne)))
+		if tsneToString() != `7,NULL,NULL` {
+			t.Fatal("Embedded null scalar marshal #2 did not match: " + tsneToString())
+		}
+	})
 }
 
 func TestRawBytes(t *testing.T) {
@@ -615,6 +735,10 @@ func TestNamed(t *testing.T) {
 	type t4 struct {
 		T1V1, T1V2 T1
 	}
+	type t5 struct {
+		T1
+		T1P *T1
+	}
 	const expectedResult = `{"E":1.1,"T2V":{"C":"str","A":5,"BC":10,"D":"YWI="},"F":true,"A":20}`
 
 	//Create a temporary table and fill it with values
@@ -686,6 +810,523 @@ func TestNamed(t *testing.T) {
 			t.Fatal(fmt.Sprintf("Incorrect error received: %v", err))
 		}
 	})
+
+	t.Run("Embedded by value and pointer", func(t *testing.T) {
+		t5v := t5{T1P: new(T1)}
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query("SELECT BC+1 as `T1P.BC`, BC as `T1.BC`, `T2V.T1.A`+1 as `T1P.A`, `T2V.T1.A` as `T1.A` FROM goTest4")), &t5v)))
+		if t5v.T1.A != 5 || t5v.T1P.A != 6 || t5v.T1.BC != 10 || t5v.T1P.BC != 11 {
+			t.Fatal(fmt.Sprintf("Values to not match (%d,%d,%d,%d)!=(%d,%d,%d,%d)", t5v.T1.A, t5v.T1P.A, t5v.T1.BC, t5v.T1P.BC, 5, 6, 10, 11))
+		}
+	})
+}
+
+func TestJSONFields(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//Test structure and results
+	type jsonSub struct {
+		X int
+		Y string
+	}
+	type t1 struct {
+		A    int
+		Meta jsonSub `gfs:"json"`
+		B    string
+		Sub  struct{ C int }
+		P    *jsonSub       `gfs:"json"`
+		M    map[string]int `gfs:"json"`
+	}
+	const expectedResult = `{"A":1,"Meta":{"X":2,"Y":"y"},"B":"b","Sub":{"C":3},"P":{"X":4,"Y":"z"},"M":{"k":5}}`
+	const selectVals = `1 AS A, '{"X":2,"Y":"y"}' AS Meta, 'b' AS B, 3 AS C, '{"X":4,"Y":"z"}' AS P, '{"k":5}' AS M`
+
+	t.Run("Index", func(t *testing.T) {
+		t1v := t1{P: new(jsonSub)}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT `+selectVals)), &t1v)))
+		if str := failOnErrT(t, fErr(json.Marshal(t1v))); string(str) != expectedResult {
+			t.Fatal("Structure json marshal did not match: " + string(str))
+		}
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		t1v := t1{P: new(jsonSub)}
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT M, P, C, B, Meta, A FROM (SELECT `+selectVals+`) AS x`)), &t1v)))
+		if str := failOnErrT(t, fErr(json.Marshal(t1v))); string(str) != expectedResult {
+			t.Fatal("Structure json marshal did not match: " + string(str))
+		}
+	})
+
+	t.Run("NULL and reuse", func(t *testing.T) {
+		t1v := t1{P: new(jsonSub), Meta: jsonSub{5, "old"}}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, NULL, 'b', 3, '{"Y":"z"}', NULL`)), &t1v)))
+		if str := failOnErrT(t, fErr(json.Marshal(t1v))); string(str) != `{"A":1,"Meta":{"X":0,"Y":""},"B":"b","Sub":{"C":3},"P":{"X":0,"Y":"z"},"M":null}` {
+			t.Fatal("Structure json marshal did not match: " + string(str))
+		}
+	})
+
+	t.Run("GeoJSON geometry", func(t *testing.T) {
+		type geoJSON struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		}
+		var g struct {
+			ID   int
+			Geom geoJSON `gfs:"json"`
+		}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, ST_AsGeoJSON(ST_GeomFromText('POINT(1.5 -2)'))`)), &g)))
+		if g.Geom.Type != "Point" || len(g.Geom.Coordinates) != 2 || g.Geom.Coordinates[0] != 1.5 || g.Geom.Coordinates[1] != -2 {
+			t.Fatal(fmt.Sprintf("GeoJSON did not match: %+v", g.Geom))
+		}
+
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 2, ST_AsGeoJSON(NULL)`)), &g)))
+		if g.Geom.Type != "" || g.Geom.Coordinates != nil {
+			t.Fatal(fmt.Sprintf("NULL GeoJSON did not match: %+v", g.Geom))
+		}
+	})
+}
+
+func TestNamedBool(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type active bool
+	type t1 struct {
+		A active
+		P *active
+	}
+	t1v := t1{P: new(active)}
+
+	t.Run("True", func(t *testing.T) {
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, true`)), &t1v)))
+		if !t1v.A || !*t1v.P {
+			t.Fatal(fmt.Sprintf("Values do not match (%v,%v)!=(true,true)", t1v.A, *t1v.P))
+		}
+	})
+
+	t.Run("NULL is false", func(t *testing.T) {
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT NULL, NULL`)), &t1v)))
+		if t1v.A || *t1v.P {
+			t.Fatal(fmt.Sprintf("Values do not match (%v,%v)!=(false,false)", t1v.A, *t1v.P))
+		}
+	})
+
+	t.Run("Scalars", func(t *testing.T) {
+		var a active
+		var p = new(active)
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, 0`)), &a, p)))
+		if !a || *p {
+			t.Fatal(fmt.Sprintf("Values do not match (%v,%v)!=(true,false)", a, *p))
+		}
+	})
+}
+
+func TestBoolTruthiness(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	for _, d := range []struct {
+		in       string
+		expected bool
+	}{
+		{"NULL", false}, {"'0'", false}, {"'1'", true}, {"'-1'", true}, {"'2'", true}, {"'10'", true}, {"'00'", false}, {"''", false},
+		{"'true'", true}, {"'t'", true}, {"'yes'", true}, {"'Y'", true}, {"'false'", false}, {"'f'", false}, {"'no'", false},
+		{"CAST(-1 AS SIGNED)", true}, {"true", true}, {"false", false},
+	} {
+		var b bool
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT `+d.in)), &b)))
+		if b != d.expected {
+			t.Fatal(fmt.Sprintf("Bool value for %s does not match %v!=%v", d.in, b, d.expected))
+		}
+	}
+}
+
+// binKey is stored as a 1 byte prefix followed by a big endian uint32, and keeps its raw bytes
+type binKey struct {
+	Prefix byte
+	ID     uint32
+	raw    []byte
+}
+
+func (k *binKey) UnmarshalBinary(data []byte) error {
+	if len(data) != 5 {
+		return fmt.Errorf("binKey must be 5 bytes, got %d", len(data))
+	}
+	*k = binKey{data[0], uint32(data[1])<<24 | uint32(data[2])<<16 | uint32(data[3])<<8 | uint32(data[4]), data}
+	return nil
+}
+
+func TestBinaryUnmarshaler(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type row struct {
+		ID  int
+		Key binKey
+		P   *binKey
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(row{}))).CreateReader()
+	rows := failOnErrT(t, fErr(tx.Query(`SELECT 1, CAST(X'0100000002' AS BINARY(5)), CAST(NULL AS BINARY(5)) UNION ALL SELECT 2, X'03000000FF', X'0400000100'`)))
+	var results [2]row
+	for i := range results {
+		if !rows.Next() {
+			t.Fatal("Missing row")
+		}
+		failOnErrT(t, fErr(0, rr.ScanRows(rows, &results[i])))
+	}
+	_ = rows.Close()
+
+	//The first row’s raw bytes must not be overwritten by the second scan
+	if r := results[0]; r.Key.Prefix != 1 || r.Key.ID != 2 || !bytes.Equal(r.Key.raw, []byte{1, 0, 0, 0, 2}) || r.P != nil {
+		t.Fatal(fmt.Sprintf("Row 1 did not match: %+v", r))
+	} else if r := results[1]; r.Key.Prefix != 3 || r.Key.ID != 255 || r.P == nil || r.P.Prefix != 4 || r.P.ID != 256 {
+		t.Fatal(fmt.Sprintf("Row 2 did not match: %+v", r))
+	}
+
+	//Errors from UnmarshalBinary are returned
+	var k binKey
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT X'0102'`)), &k); err == nil || !strings.Contains(err.Error(), "binKey must be 5 bytes") {
+		t.Fatal(fmt.Sprintf("Expected UnmarshalBinary error, got: %v", err))
+	}
+
+	//NULL leaves the zero value
+	k = binKey{1, 2, []byte{1}}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT CAST(NULL AS BINARY(5))`)), &k)))
+	if k.Prefix != 0 || k.ID != 0 || k.raw != nil {
+		t.Fatal(fmt.Sprintf("NULL did not set the zero value: %+v", k))
+	}
+
+	//encoding.TextUnmarshaler takes precedence over encoding.BinaryUnmarshaler
+	var tb textBinKey
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'a'`)), &tb)))
+	if tb.Via != "text:a" {
+		t.Fatal("Expected UnmarshalText to be used, got: " + tb.Via)
+	}
+
+	//Structures that only have the method promoted from an embedded member are recursed into
+	var ec embeddedBinCode
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT X'0102', 8`)), &ec)))
+	if ec.Code != 258 || ec.N != 8 {
+		t.Fatal(fmt.Sprintf("Embedded binary unmarshaler structure did not match: %+v", ec))
+	}
+}
+
+// textBinKey implements both encoding.TextUnmarshaler and encoding.BinaryUnmarshaler, and records which was used
+type textBinKey struct {
+	Via string
+}
+
+func (k *textBinKey) UnmarshalText(data []byte) error {
+	k.Via = "text:" + string(data)
+	return nil
+}
+func (k *textBinKey) UnmarshalBinary([]byte) error {
+	k.Via = "binary"
+	return nil
+}
+
+// binCode is stored as a big endian uint16. It is embedded in embeddedBinCode, which must be recursed into (2 columns) instead of using the promoted method.
+type binCode struct {
+	Code uint16
+}
+type embeddedBinCode struct {
+	binCode
+	N int
+}
+
+func (c *binCode) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return fmt.Errorf("binCode must be 2 bytes, got %d", len(data))
+	}
+	c.Code = uint16(data[0])<<8 | uint16(data[1])
+	return nil
+}
+
+func TestEmbeddedTime(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//The structure gets UnmarshalText (and others) promoted from time.Time, but must still be recursed into
+	type stamped struct {
+		time.Time
+		By string
+		N  int
+	}
+	if n := failOnErrT(t, fErr(gf.ModelStruct(stamped{}))).ExpectedColumns(); n != 3 {
+		t.Fatal(fmt.Sprintf("Expected 3 columns, got %d", n))
+	}
+	var v stamped
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '2001-02-03 04:05:06', 'me', 5`)), &v)))
+	if !v.Time.Equal(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)) || v.By != "me" || v.N != 5 {
+		t.Fatal(fmt.Sprintf("Embedded time structure did not match: %+v", v))
+	}
+}
+
+func TestWriterMembers(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//Columns are appended to writers, and NULL writes nothing
+	type writers struct {
+		Buf  *bytes.Buffer
+		W    io.Writer
+		SB   strings.Builder
+		Null bytes.Buffer
+	}
+	v := writers{Buf: bytes.NewBufferString("x"), W: new(strings.Builder)}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'a', 'b', 'c', NULL`)), &v)))
+	if v.Buf.String() != "xa" || v.W.(*strings.Builder).String() != "b" || v.SB.String() != "c" || v.Null.Len() != 0 {
+		t.Fatal(fmt.Sprintf("Writers did not match: %s %s %s %s", v.Buf.String(), v.W.(*strings.Builder).String(), v.SB.String(), v.Null.String()))
+	}
+
+	//Structures that get Write() promoted from an embedded member are still recursed into
+	type embeddedBuffer struct {
+		bytes.Buffer
+		ID int
+	}
+	if n := failOnErrT(t, fErr(gf.ModelStruct(embeddedBuffer{}))).ExpectedColumns(); n == 1 {
+		t.Fatal("Structure embedding bytes.Buffer was modeled as a writer")
+	}
+}
+
+// bsPoint implements gofastersql.BytesScanner for columns in the format “X,Y”
+type bsPoint struct {
+	X, Y int
+}
+
+func (bp *bsPoint) ScanBytes(in []byte) error {
+	_, err := fmt.Sscanf(string(in), "%d,%d", &bp.X, &bp.Y)
+	return err
+}
+
+// bsCode implements gofastersql.BytesScanner, but as an int typedef it uses the built-in int conversion
+type bsCode int
+
+func (bc *bsCode) ScanBytes([]byte) error {
+	return errors.New("bsCode.ScanBytes should not be called")
+}
+
+func TestBytesScanner(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//Structures that get ScanBytes() promoted from an embedded member (bsPoint) are still recursed into
+	type embeddedBytesScanner struct {
+		bsPoint
+		ID int
+	}
+	var v struct {
+		P    bsPoint
+		Code bsCode
+		E    embeddedBytesScanner
+	}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '1,2', 3, '4,5', 6`)), &v)))
+	if v.P != (bsPoint{1, 2}) || v.Code != 3 || v.E.bsPoint != (bsPoint{4, 5}) || v.E.ID != 6 {
+		t.Fatal(fmt.Sprintf("Bytes scanner members did not match: %+v", v))
+	}
+}
+
+// packedPair implements gofastersql.BytesConsumer for columns packed as 2 bytes
+type packedPair struct {
+	A, B uint8
+}
+
+func (pp *packedPair) ConsumeBytes(in []byte) (int, error) {
+	if in == nil {
+		*pp = packedPair{}
+		return 0, nil
+	} else if len(in) < 2 {
+		return 0, fmt.Errorf("packedPair needs 2 bytes, got %d", len(in))
+	}
+	*pp = packedPair{in[0], in[1]}
+	return 2, nil
+}
+
+func TestBytesConsumer(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	var v struct {
+		P packedPair
+		N packedPair
+	}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT X'0102', NULL`)), &v)))
+	if v.P != (packedPair{1, 2}) || v.N != (packedPair{}) {
+		t.Fatal(fmt.Sprintf("Bytes consumer members did not match: %+v", v))
+	}
+
+	//Columns that are not fully consumed return an error for the member
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT X'010203', NULL`)), &v); err == nil || err.Error() != "Error on P: 1 trailing bytes in column were not consumed" {
+		t.Fatal(fmt.Sprintf("Expected a trailing bytes error, got: %v", err))
+	}
+}
+
+// scanPoint implements sql.Scanner for columns in the format “X,Y”
+type scanPoint struct {
+	X, Y int
+}
+
+func (sp *scanPoint) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("scanPoint cannot scan %T", src)
+	}
+	_, err := fmt.Sscanf(string(b), "%d,%d", &sp.X, &sp.Y)
+	return err
+}
+
+func TestSQLScanner(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	t.Run("Scanner member", func(t *testing.T) {
+		var v struct {
+			P  scanPoint
+			PP *scanPoint
+		}
+		v.PP = new(scanPoint)
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '1,2', '3,4'`)), &v)))
+		if v.P != (scanPoint{1, 2}) || *v.PP != (scanPoint{3, 4}) {
+			t.Fatal(fmt.Sprintf("Scanner members did not match: %+v %+v", v.P, *v.PP))
+		}
+	})
+
+	//Structures that get Scan() promoted from an embedded member are still recursed into
+	t.Run("Embedded scanner", func(t *testing.T) {
+		type embeddedScanner struct {
+			sql.NullString
+			ID int
+		}
+		var v embeddedScanner
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'str', 5`)), &v)))
+		if !v.Valid || v.String != "str" || v.ID != 5 {
+			t.Fatal(fmt.Sprintf("Embedded scanner structure did not match: %+v", v))
+		}
+	})
+}
+
+type genericRow[T any] struct {
+	ID    int
+	Value T
+}
+
+func TestGenericStructs(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	t.Run("Separate models per instantiation", func(t *testing.T) {
+		smStr := failOnErrT(t, fErr(gf.ModelStruct(genericRow[string]{})))
+		smInt := failOnErrT(t, fErr(gf.ModelStruct(genericRow[int]{})))
+		smNested := failOnErrT(t, fErr(gf.ModelStruct(genericRow[genericRow[bool]]{})))
+		if smStr.Equals(smInt) || smStr.Equals(smNested) || smInt.Equals(smNested) {
+			t.Fatal("Generic instantiations share a model")
+		} else if smNested.ExpectedColumns() != 3 {
+			t.Fatal(fmt.Sprintf("Nested generic instantiation has the wrong number of columns: %d", smNested.ExpectedColumns()))
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		var rStr genericRow[string]
+		var rInt genericRow[int]
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, 'str'`)), &rStr)))
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 2, 20`)), &rInt)))
+		if rStr != (genericRow[string]{1, "str"}) || rInt != (genericRow[int]{2, 20}) {
+			t.Fatal(fmt.Sprintf("Generic structures did not match: %v %v", rStr, rInt))
+		}
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		var rNested genericRow[genericRow[bool]]
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT true AS "Value.Value", 4 AS "Value.ID", 3 AS ID`)), &rNested)))
+		if rNested != (genericRow[genericRow[bool]]{3, genericRow[bool]{4, true}}) {
+			t.Fatal(fmt.Sprintf("Generic structure did not match: %v", rNested))
+		}
+	})
+}
+
+func TestDuration(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type durations struct {
+		NS      time.Duration
+		Seconds time.Duration `gfs:"duration:s"`
+		Str     time.Duration `gfs:"duration:string"`
+		Null    time.Duration `gfs:"duration:ms"`
+	}
+
+	t.Run("Integer and string forms", func(t *testing.T) {
+		d := durations{Null: time.Hour}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT CAST(1500000000 AS SIGNED), 90, '1h30m', NULL`)), &d)))
+		if d != (durations{1500 * time.Millisecond, 90 * time.Second, 90 * time.Minute, 0}) {
+			t.Fatal(fmt.Sprintf("Durations did not match: %+v", d))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var d durations
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, 1, '1x', 1`)), &d); err == nil {
+			t.Fatal("Expected an error for an invalid duration string")
+		}
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, 9223372036854775807, '1s', 1`)), &d); err == nil {
+			t.Fatal("Expected an error for an out of range duration")
+		}
+		type badUnit struct {
+			D time.Duration `gfs:"duration:days"`
+		}
+		if _, err := gf.ModelStruct(badUnit{}); err == nil {
+			t.Fatal("Expected an error for an unknown duration unit")
+		}
+	})
+}
+
+func TestNullTimePrecision(t *testing.T) {
+	const expectedJSON = `"2001-02-03T05:06:07.123456Z"`
+	expectedTime := time.Date(2001, 2, 3, 5, 6, 7, 123456000, time.UTC)
+	roundTrip := func(t *testing.T, nt nulltypes.NullTime) {
+		t.Helper()
+		str := failOnErrT(t, fErr(json.Marshal(nt)))
+		if string(str) != expectedJSON {
+			t.Fatal("NullTime json marshal did not match: " + string(str))
+		}
+		var nt2 nulltypes.NullTime
+		failOnErrT(t, fErr(0, json.Unmarshal(str, &nt2)))
+		if nt2.IsNull || !nt2.Val.Equal(expectedTime) {
+			t.Fatal("NullTime json unmarshal did not match: " + nt2.String())
+		}
+	}
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		roundTrip(t, nulltypes.NullTime{Val: expectedTime})
+
+		var nt nulltypes.NullTime
+		failOnErrT(t, fErr(0, json.Unmarshal([]byte("null"), &nt)))
+		if !nt.IsNull {
+			t.Fatal("NullTime json unmarshal of null was not null")
+		}
+	})
+
+	t.Run("Scan round trip", func(t *testing.T) {
+		tx := failOnErrT(t, fErr(setupSQLConnect()))
+		defer rollbackTransactionAndRows(tx, nil, 0)
+
+		var nt nulltypes.NullTime
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT CAST('2001-02-03 05:06:07.123456' AS DATETIME(6))`)), &nt)))
+		if nt.String() != `2001-02-03 05:06:07.123456` {
+			t.Fatal("NullTime scan did not match: " + nt.String())
+		}
+		roundTrip(t, nt)
+	})
 }
 
 //------------------------------Benchmark ScanRows------------------------------
@@ -936,3 +1577,1225 @@ func Benchmark_MultiItem_Native(b *testing.B) {
 		func(rows *sql.Rows, ts1 *testStruct1) error { return rows.Scan(pointers...) },
 	)
 }
+
+type blob []byte
+type rawBytesTypedef sql.RawBytes
+type byteTypedef byte
+
+func TestByteSliceTypedefs(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type byteSlices struct {
+		B   blob
+		RB  rawBytesTypedef
+		BT  []byteTypedef
+		PRB *rawBytesTypedef
+	}
+	r := failOnErrT(t, fErr(gf.ModelStruct(byteSlices{}))).CreateReader()
+
+	t.Run("Scan Rows", func(t *testing.T) {
+		rows := failOnErrT(t, fErr(tx.Query(`SELECT 'b1', 'rb1', 'bt1', 'p1' UNION ALL SELECT 'b2', 'rb2', 'bt2', 'p2'`)))
+		defer func() { safeCloseRows(rows) }()
+
+		//The typedefs are copied, so they must survive the next scan
+		out := make([]byteSlices, 2)
+		for i := range out {
+			out[i].PRB = new(rawBytesTypedef)
+			rows.Next()
+			failOnErrT(t, fErr(0, r.ScanRows(rows, &out[i])))
+		}
+		for i, v := range out {
+			n := fmt.Sprintf("%d", i+1)
+			bt := make([]byte, len(v.BT))
+			for j, c := range v.BT {
+				bt[j] = byte(c)
+			}
+			if string(v.B) != "b"+n || string(v.RB) != "rb"+n || string(bt) != "bt"+n || string(*v.PRB) != "p"+n {
+				t.Fatal(fmt.Sprintf("Byte slice typedefs #%d did not match: %s %s %s %s", i+1, v.B, v.RB, bt, *v.PRB))
+			}
+		}
+	})
+
+	t.Run("Scan Row", func(t *testing.T) {
+		v := byteSlices{PRB: new(rawBytesTypedef)}
+		failOnErrT(t, fErr(0, r.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'b', 'rb', NULL, 'p'`)), &v)))
+		if string(v.B) != "b" || string(v.RB) != "rb" || v.BT != nil || string(*v.PRB) != "p" {
+			t.Fatal(fmt.Sprintf("Byte slice typedefs did not match: %s %s %v %s", v.B, v.RB, v.BT, *v.PRB))
+		}
+	})
+}
+
+type nullEnum uint8
+
+func TestGenericNull(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type nulls struct {
+		E  nulltypes.Null[nullEnum]
+		S  nulltypes.Null[string]
+		T  nulltypes.Null[time.Time]
+		RB nulltypes.Null[sql.RawBytes]
+	}
+	resArr := []string{
+		`{"E":3,"S":"str","T":"2001-02-03T04:05:06Z","RB":"rb"}`, //Byte slices are strings like NullByteArray instead of base64
+		`{"E":null,"S":null,"T":null,"RB":null}`,
+	}
+
+	for i, query := range []string{`SELECT 3, 'str', '2001-02-03 04:05:06', 'rb'`, `SELECT NULL, NULL, NULL, NULL`} {
+		v := nulls{E: nulltypes.Null[nullEnum]{Val: 9}}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v)))
+		if str := failOnErrT(t, fErr(json.Marshal(v))); string(str) != resArr[i] {
+			t.Fatal(fmt.Sprintf("Generic null structure json marshal #%d did not match: %s", i+1, string(str)))
+		}
+		var back nulls
+		failOnErrT(t, fErr(0, json.Unmarshal([]byte(resArr[i]), &back)))
+		if back.E != v.E || back.S != v.S || back.T.IsNull != v.T.IsNull || (!v.T.IsNull && !back.T.Val.Equal(v.T.Val)) { //NULL times hold time.Unix(0, 0) when scanned, which does not survive the json round trip
+			t.Fatal(fmt.Sprintf("Generic null structure json unmarshal #%d did not match: %+v", i+1, back))
+		}
+	}
+
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 256, NULL, NULL, NULL`)), new(nulls)); err == nil {
+		t.Fatal("Expected an error for an out of range value")
+	}
+}
+
+func TestNilOnNull(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type side struct {
+		ID   nulltypes.NullInt64
+		Name nulltypes.NullString
+	}
+	type inner struct {
+		X nulltypes.NullInt64
+	}
+	type outer struct {
+		ID nulltypes.NullInt64
+		In *inner `gfs:"nilonnull"`
+	}
+	type joined struct {
+		ID int
+		S  *side  `gfs:"nilonnull"`
+		O  *outer `gfs:"nilonnull"`
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(joined{}))).CreateReader()
+	scan := func(t *testing.T, query string, v *joined) {
+		t.Helper()
+		rows := failOnErrT(t, fErr(tx.Query(query)))
+		failOnErrT(t, fErr(0, rr.ScanRow(rows, v)))
+	}
+
+	t.Run("All NULL", func(t *testing.T) {
+		v := joined{S: &side{}, O: &outer{In: &inner{}}}
+		scan(t, `SELECT 1, NULL, NULL, NULL, NULL`, &v)
+		if v.ID != 1 || v.S != nil || v.O != nil {
+			t.Fatal(fmt.Sprintf("All NULL structure pointers were not set to nil: %+v", v))
+		}
+	})
+
+	t.Run("Partly NULL", func(t *testing.T) {
+		var v joined
+		scan(t, `SELECT 2, 3, NULL, 4, NULL`, &v)
+		if v.S == nil || v.S.ID.Val != 3 || !v.S.Name.IsNull {
+			t.Fatal(fmt.Sprintf("Partly NULL structure pointer was not allocated and filled: %+v", v.S))
+		} else if v.O == nil || v.O.ID.Val != 4 || v.O.In != nil {
+			t.Fatal(fmt.Sprintf("Nested all NULL structure pointer was not set to nil: %+v", v.O))
+		}
+	})
+
+	t.Run("Nested not NULL", func(t *testing.T) {
+		var v joined
+		scan(t, `SELECT 3, NULL, NULL, NULL, 5`, &v)
+		if v.S != nil {
+			t.Fatal(fmt.Sprintf("All NULL structure pointer was allocated: %+v", v.S))
+		} else if v.O == nil || !v.O.ID.IsNull || v.O.In == nil || v.O.In.X.Val != 5 {
+			t.Fatal(fmt.Sprintf("Parent of a nested not NULL structure pointer was not allocated: %+v", v.O))
+		}
+	})
+}
+
+func TestPresenceMembers(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type geo struct {
+		Lat, Lng nulltypes.NullFloat64
+	}
+	type addr struct {
+		City   nulltypes.NullString
+		Geo    geo  `gfs:"present:HasGeo"`
+		HasGeo bool `gfs:"-"`
+	}
+	type person struct {
+		ID      int
+		Addr    addr `gfs:"present:HasAddr"`
+		HasAddr bool `gfs:"-"`
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(person{}))).CreateReader()
+
+	for _, test := range []struct {
+		name            string
+		query           string
+		hasAddr, hasGeo bool
+	}{
+		{"All NULL", `SELECT 1, NULL, NULL, NULL`, false, false},
+		{"Not NULL", `SELECT 2, 'city', NULL, NULL`, true, false},
+		{"Nested not NULL", `SELECT 3, NULL, NULL, 1.5`, true, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v := person{HasAddr: !test.hasAddr, Addr: addr{HasGeo: !test.hasGeo}}
+			failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(test.query))), &v)))
+			if v.HasAddr != test.hasAddr || v.Addr.HasGeo != test.hasGeo {
+				t.Fatal(fmt.Sprintf("Presence members did not match: HasAddr=%v HasGeo=%v", v.HasAddr, v.Addr.HasGeo))
+			}
+		})
+	}
+
+	//The presence member must be a bool tagged with gfs:"-"
+	type badPresence struct {
+		G   geo `gfs:"present:Has"`
+		Has bool
+	}
+	if _, err := gf.ModelStruct(badPresence{}); err == nil {
+		t.Fatal("Expected an error for a presence member that is not skipped")
+	}
+}
+
+func TestNamedSubset(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type sub struct {
+		A int
+		C string
+	}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(sub{})))
+
+	t.Run("Extra columns are discarded", func(t *testing.T) {
+		var v sub
+		rr := sm.CreateReaderNamedSubset()
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 'b' AS B, 'c' AS C, 4 AS D, 1 AS A`))), &v)))
+		if v != (sub{1, "c"}) {
+			t.Fatal(fmt.Sprintf("Subset did not match: %+v", v))
+		}
+	})
+
+	t.Run("Missing member", func(t *testing.T) {
+		var v sub
+		rr := sm.CreateReaderNamedSubset()
+		if err := rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 1 AS A, 2 AS B`))), &v); err == nil || err.Error() != "0 matches found for member “C”" {
+			t.Fatal(fmt.Sprintf("Expected a missing member error, got: %v", err))
+		}
+	})
+}
+
+func TestAllowDuplicateColumns(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type dup struct {
+		A int
+		B string
+	}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(dup{})))
+	const query = `SELECT 1 AS A, 'b' AS B, 2 AS A, 'c' AS B`
+
+	t.Run("Error by default", func(t *testing.T) {
+		var v dup
+		if err := sm.CreateReaderNamed().ScanRow(failOnErrT(t, fErr(tx.Query(query))), &v); err == nil {
+			t.Fatal("Expected an error for duplicate columns")
+		}
+	})
+
+	//The first matching columns are scanned and the duplicates are discarded
+	t.Run("Discarded", func(t *testing.T) {
+		var v dup
+		rr := sm.CreateReaderNamed()
+		failOnErrT(t, fErr(0, rr.AllowDuplicateColumns(true)))
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(query))), &v)))
+		if v != (dup{1, "b"}) {
+			t.Fatal(fmt.Sprintf("Duplicate columns were not discarded: %+v", v))
+		}
+		if err := rr.AllowDuplicateColumns(false); err == nil {
+			t.Fatal("Expected an error for changing the mode after matching")
+		}
+	})
+}
+
+func TestNamedPrefixed(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type user struct {
+		ID   int
+		Name string
+	}
+	type account struct {
+		ID     int
+		UserID int
+	}
+	var u user
+	var a account
+	sm := failOnErrT(t, fErr(gf.ModelStruct(&u, &a)))
+
+	//Members with the same name in different structures are routed by their prefix
+	rr := failOnErrT(t, fErr(sm.CreateReaderNamedPrefixed("u.", "a.")))
+	failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query("SELECT 2 AS `a.ID`, 'n' AS `u.Name`, 1 AS `u.ID`, 1 AS `a.UserID`"))), &u, &a)))
+	if u != (user{1, "n"}) || a != (account{2, 1}) {
+		t.Fatal(fmt.Sprintf("Prefixed members did not match: %+v %+v", u, a))
+	}
+
+	//Unprefixed columns do not match
+	rr = failOnErrT(t, fErr(sm.CreateReaderNamedPrefixed("u.", "a.")))
+	if err := rr.ScanRow(failOnErrT(t, fErr(tx.Query("SELECT 2 AS `a.ID`, 'n' AS Name, 1 AS `u.ID`, 1 AS `a.UserID`"))), &u, &a); err == nil {
+		t.Fatal("Expected an error for an unprefixed column")
+	}
+
+	if _, err := sm.CreateReaderNamedPrefixed("u."); err == nil {
+		t.Fatal("Expected an error for the wrong number of prefixes")
+	}
+}
+
+func TestMatchColumnsAndFreeze(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type row struct {
+		A int
+		B string
+	}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(row{})))
+	const query = `SELECT 'b' AS B, 1 AS A`
+
+	t.Run("Errors before matching", func(t *testing.T) {
+		if _, err := sm.CreateReaderNamed().Freeze(); err == nil {
+			t.Fatal("Expected an error for freezing before matching")
+		} else if _, err := sm.CreateReader().Freeze(); err == nil {
+			t.Fatal("Expected an error for freezing a non-named reader")
+		}
+	})
+
+	t.Run("Match ahead of time", func(t *testing.T) {
+		var v row
+		rr := sm.CreateReaderNamed()
+		failOnErrT(t, fErr(0, rr.MatchColumns([]string{"B", "A"})))
+		if err := rr.MatchColumns([]string{"B", "A"}); err == nil {
+			t.Fatal("Expected an error for matching twice")
+		}
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(query))), &v)))
+		if v != (row{1, "b"}) {
+			t.Fatal(fmt.Sprintf("Matched reader did not match: %+v", v))
+		}
+	})
+
+	//The frozen reader is index based in the matched column order
+	t.Run("Frozen", func(t *testing.T) {
+		var v row
+		rr := sm.CreateReaderNamed()
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(query))), &v)))
+		frozen := failOnErrT(t, fErr(rr.Freeze()))
+		v = row{}
+		failOnErrT(t, fErr(0, frozen.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 'c', 2`))), &v)))
+		if v != (row{2, "c"}) {
+			t.Fatal(fmt.Sprintf("Frozen reader did not match: %+v", v))
+		}
+	})
+
+	//The frozen reader keeps the settings of the reader it was frozen from
+	t.Run("Frozen settings", func(t *testing.T) {
+		var v row
+		rr := sm.CreateReaderNamedWithOptions(gf.ReaderOptions{KeepRawRow: true})
+		rr.SafeConverters(true)
+		rr.OnField(func(name string, ptr any) error {
+			if s, ok := ptr.(*string); ok {
+				*s = strings.ToUpper(*s)
+			}
+			return nil
+		})
+		failOnErrT(t, fErr(0, rr.MatchColumns([]string{"B", "A"})))
+		frozen := failOnErrT(t, fErr(rr.Freeze()))
+		failOnErrT(t, fErr(0, frozen.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 'c', 2`))), &v)))
+		if v != (row{2, "C"}) {
+			t.Fatal(fmt.Sprintf("Frozen reader did not keep its field hook: %+v", v))
+		} else if raw := frozen.LastRawRow(); len(raw) != 2 || string(raw[0]) != "c" {
+			t.Fatal(fmt.Sprintf("Frozen reader did not keep its raw row: %q", raw))
+		}
+	})
+}
+
+type shape interface{ Area() float64 }
+type circle struct{ R float64 }
+type square int
+
+func (c circle) Area() float64 { return 3 * c.R * c.R }
+func (s square) Area() float64 { return float64(s * s) }
+
+func TestTypeResolver(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	gf.RegisterTypeResolver("testShapes", func(discriminator string) (any, error) {
+		switch discriminator {
+		case "circle":
+			return new(circle), nil
+		case "square":
+			return new(square), nil
+		}
+		return nil, fmt.Errorf("Unknown shape “%s”", discriminator)
+	})
+
+	type shapeRow struct {
+		Kind  string
+		Shape shape `gfs:"resolve:testShapes:Kind"`
+	}
+	t.Run("Dispatch", func(t *testing.T) {
+		//The resolvers return pointers, which are stored as is since they implement the interface
+		for _, d := range []struct {
+			query, expected string
+		}{
+			{`SELECT 'circle', '{"R":2}'`, "*test.circle=12"},
+			{`SELECT 'square', '5'`, "*test.square=25"},
+		} {
+			v := shapeRow{Shape: circle{9}}
+			failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(d.query)), &v)))
+			if s := fmt.Sprintf("%T=%g", v.Shape, v.Shape.Area()); s != d.expected {
+				t.Fatal(fmt.Sprintf("Shape for “%s” does not match: %s!=%s", d.query, s, d.expected))
+			}
+		}
+
+		v := shapeRow{Shape: circle{9}}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'square', NULL`)), &v)))
+		if v.Shape != nil {
+			t.Fatal(fmt.Sprintf("NULL did not clear the shape: %#v", v.Shape))
+		}
+
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'triangle', '1'`)), &v); err == nil || !strings.Contains(err.Error(), "Unknown shape “triangle”") {
+			t.Fatal(fmt.Sprintf("Expected an unknown shape error, got: %v", err))
+		}
+	})
+
+	//The discriminator must be converted first, so a payload before it sees the discriminator’s previous value
+	t.Run("Discriminator after payload", func(t *testing.T) {
+		type lateRow struct {
+			Shape shape `gfs:"resolve:testShapes:Kind"`
+			Kind  string
+		}
+		var v lateRow
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '5', 'square'`)), &v); err == nil || !strings.Contains(err.Error(), "Unknown shape “”") {
+			t.Fatal(fmt.Sprintf("Expected an unknown shape error, got: %v", err))
+		}
+
+		v = lateRow{Kind: "circle"}
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '{"R":1}', 'square'`)), &v)))
+		if c, ok := v.Shape.(*circle); !ok || *c != (circle{1}) || v.Kind != "square" {
+			t.Fatal(fmt.Sprintf("Expected the previous discriminator to be used: %#v", v))
+		}
+	})
+}
+
+func TestOnField(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type hookRow struct {
+		Name  string
+		Inner struct{ Secret string }
+		N     int
+	}
+	var names []string
+	rr := failOnErrT(t, fErr(gf.ModelStruct(hookRow{}))).CreateReader()
+	rr.OnField(func(name string, ptr any) error {
+		names = append(names, name)
+		if s, ok := ptr.(*string); ok && name == "Inner.Secret" {
+			*s = "***"
+		} else if n, ok := ptr.(*int); ok && *n == 0 {
+			return errors.New("N must not be 0")
+		}
+		return nil
+	})
+	scan := func(query string) (v hookRow, err error) {
+		names = nil
+		err = rr.ScanRow(failOnErrT(t, fErr(tx.Query(query))), &v)
+		return
+	}
+
+	t.Run("Called per member", func(t *testing.T) {
+		v := failOnErrT(t, fErr(scan(`SELECT 'a', 'b', 1`)))
+		if v.Name != "a" || v.Inner.Secret != "***" || v.N != 1 {
+			t.Fatal(fmt.Sprintf("Hook was not applied: %+v", v))
+		} else if strings.Join(names, ",") != "Name,Inner.Secret,N" {
+			t.Fatal(fmt.Sprintf("Hook was called with the wrong names: %v", names))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := scan(`SELECT 'a', 'b', 0`); err == nil || err.Error() != "Error on N: N must not be 0" {
+			t.Fatal(fmt.Sprintf("Expected the hook’s error, got: %v", err))
+		}
+		if _, err := scan(`SELECT 'a', 'b', 'x'`); err == nil || !strings.HasPrefix(err.Error(), "Error on N: ") {
+			t.Fatal(fmt.Sprintf("Expected a conversion error, got: %v", err))
+		} else if strings.Join(names, ",") != "Name,Inner.Secret" {
+			t.Fatal(fmt.Sprintf("Hook was called for a member that failed to convert: %v", names))
+		}
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		rr.OnField(nil)
+		if v := failOnErrT(t, fErr(scan(`SELECT 'a', 'b', 0`))); v.Inner.Secret != "b" || names != nil {
+			t.Fatal(fmt.Sprintf("Hook was not removed: %+v", v))
+		}
+	})
+}
+
+type panicky string
+
+func TestSafeConverters(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	gf.RegisterConverter(reflect.TypeOf(panicky("")), func(in []byte, dst any) error {
+		if string(in) == "boom" {
+			panic("boom")
+		}
+		*dst.(*panicky) = panicky(in)
+		return nil
+	})
+	defer gf.RegisterConverter(reflect.TypeOf(panicky("")), nil)
+
+	type panicRow struct {
+		A int
+		P panicky
+		B string
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(panicRow{}))).CreateReader()
+
+	t.Run("Off", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected the converter’s panic")
+			}
+		}()
+		var v panicRow
+		_ = rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 1, 'boom', 'b'`))), &v)
+	})
+
+	t.Run("On", func(t *testing.T) {
+		rr.SafeConverters(true)
+		var v panicRow
+		if err := rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 1, 'boom', 'b'`))), &v); err == nil || err.Error() != "Error on P: Panic in converter: boom" {
+			t.Fatal(fmt.Sprintf("Expected the panic as a field error, got: %v", err))
+		} else if v.A != 1 || v.B != "b" {
+			t.Fatal(fmt.Sprintf("Other members were not converted: %+v", v))
+		}
+
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 2, 'ok', 'c'`))), &v)))
+		if v != (panicRow{2, "ok", "c"}) {
+			t.Fatal(fmt.Sprintf("Registered converter was not used: %+v", v))
+		}
+	})
+}
+
+func TestScanPivot(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//Duplicate pairs keep the last value, NULL is an empty string, and other columns are ignored
+	pivot := failOnErrT(t, fErr(gf.ScanPivot(failOnErrT(t, fErr(tx.Query(
+		`SELECT 'bob' AS Name, 'Jan' AS Month, '5' AS Sales, 'x' AS Other `+
+			`UNION ALL SELECT 'bob', 'Feb', '7', 'x' `+
+			`UNION ALL SELECT 'amy', 'Jan', NULL, 'x' `+
+			`UNION ALL SELECT 'bob', 'Jan', '6', 'x'`,
+	))), "Name", "Month", "Sales")))
+	if s := fmt.Sprint(pivot); s != "map[amy:map[Jan:] bob:map[Feb:7 Jan:6]]" {
+		t.Fatal(fmt.Sprintf("Pivot does not match: %s", s))
+	}
+
+	if _, err := gf.ScanPivot(failOnErrT(t, fErr(tx.Query(`SELECT 'bob' AS Name, 'Jan' AS Month`))), "Name", "Month", "Sales"); err == nil || err.Error() != "Column “Sales” not found" {
+		t.Fatal(fmt.Sprintf("Expected a missing column error, got: %v", err))
+	}
+}
+
+func TestScanValidate(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type order struct {
+		ID    int
+		Total int
+	}
+	validate := func(o order) error {
+		if o.Total < 0 {
+			return fmt.Errorf("Order %d has a negative total", o.ID)
+		}
+		return nil
+	}
+
+	t.Run("Validation errors", func(t *testing.T) {
+		valid, rowErrors, err := gf.ScanValidate(failOnErrT(t, fErr(tx.Query(`SELECT 1, 5 UNION ALL SELECT 2, -1 UNION ALL SELECT 3, 0`))), validate)
+		if err != nil {
+			t.Fatal(err)
+		} else if fmt.Sprint(valid) != "[{1 5} {3 0}]" {
+			t.Fatal(fmt.Sprintf("Valid rows do not match: %v", valid))
+		} else if len(rowErrors) != 1 || rowErrors[1] == nil || rowErrors[1].Error() != "Order 2 has a negative total" {
+			t.Fatal(fmt.Sprintf("Row errors do not match: %v", rowErrors))
+		}
+	})
+
+	//Conversion errors stop the scan and are not added to the row errors
+	t.Run("Conversion errors", func(t *testing.T) {
+		valid, rowErrors, err := gf.ScanValidate(failOnErrT(t, fErr(tx.Query(`SELECT 1, 5 UNION ALL SELECT 2, -1 UNION ALL SELECT 3, 'x' UNION ALL SELECT 4, 1`))), validate)
+		if err == nil || !strings.HasPrefix(err.Error(), "Error on Total: ") {
+			t.Fatal(fmt.Sprintf("Expected a conversion error, got: %v", err))
+		} else if fmt.Sprint(valid) != "[{1 5}]" {
+			t.Fatal(fmt.Sprintf("Valid rows do not match: %v", valid))
+		} else if len(rowErrors) != 1 || rowErrors[1] == nil {
+			t.Fatal(fmt.Sprintf("Row errors do not match: %v", rowErrors))
+		}
+	})
+}
+
+func TestInternStrings(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type status struct {
+		A string
+		B nulltypes.NullString
+	}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(status{})))
+	const query = `SELECT 'ok', 'ok' UNION ALL SELECT 'ok', NULL UNION ALL SELECT 'new', 'ok'`
+
+	//Returns the rows and the string data pointer of each non-empty string
+	scanAll := func(rr *gf.RowReader) (ret []status, ptrs []*byte) {
+		rows := failOnErrT(t, fErr(tx.Query(query)))
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var v status
+			failOnErrT(t, fErr(0, rr.ScanRows(rows, &v)))
+			ret = append(ret, v)
+			ptrs = append(ptrs, unsafe.StringData(v.A), unsafe.StringData(v.B.Val))
+		}
+		return
+	}
+
+	t.Run("On", func(t *testing.T) {
+		vals, ptrs := scanAll(sm.CreateReaderWithOptions(gf.ReaderOptions{InternStrings: true}))
+		if fmt.Sprint(vals) != "[{ok ok} {ok NULL} {new ok}]" {
+			t.Fatal(fmt.Sprintf("Values do not match: %v", vals))
+		}
+		//Every “ok” (across members and rows) shares the same string
+		for _, i := range []int{1, 2, 5} {
+			if ptrs[i] != ptrs[0] {
+				t.Fatal(fmt.Sprintf("String #%d was not interned", i))
+			}
+		}
+		if ptrs[4] == ptrs[0] {
+			t.Fatal("Different strings were interned together")
+		}
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		if _, ptrs := scanAll(sm.CreateReader()); ptrs[0] == ptrs[2] {
+			t.Fatal("Strings were interned without the option")
+		}
+	})
+}
+
+func TestTrimNumericSpaces(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+	defer func(on bool) { gf.TrimNumericSpaces = on }(gf.TrimNumericSpaces)
+
+	type padded struct {
+		I  int
+		U  uint8
+		F  float64
+		NI nulltypes.NullInt32
+		S  string
+	}
+	const query = `SELECT ' 42 ', '7  ', '  1.5', ' -3 ', ' s '`
+
+	gf.TrimNumericSpaces = false
+	var v padded
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v); err == nil || strings.Count(err.Error(), "Error on ") != 4 {
+		t.Fatal(fmt.Sprintf("Expected an error for each numeric member, got: %v", err))
+	}
+
+	//Strings keep their spaces
+	gf.TrimNumericSpaces = true
+	v = padded{}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v)))
+	if v.I != 42 || v.U != 7 || v.F != 1.5 || v.NI.IsNull || v.NI.Val != -3 || v.S != " s " {
+		t.Fatal(fmt.Sprintf("Trimmed values do not match: %+v", v))
+	}
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '4 2', '7', '1', '1', ''`)), &v); err == nil || !strings.HasPrefix(err.Error(), "Error on I: ") {
+		t.Fatal(fmt.Sprintf("Expected inner spaces to fail, got: %v", err))
+	}
+}
+
+func TestZeroDateHandling(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+	defer func(mode gf.ZeroDateMode) { gf.ZeroDateHandling = mode }(gf.ZeroDateHandling)
+
+	type dates struct {
+		T  time.Time
+		NT nulltypes.NullTime
+	}
+	for _, query := range []string{`SELECT '0000-00-00 00:00:00', '0000-00-00 00:00:00'`, `SELECT '0000-00-00', '0000-00-00'`} {
+		for mode, expected := range map[gf.ZeroDateMode]time.Time{
+			gf.ZeroDateToZeroTime: {},
+			gf.ZeroDateToUnixZero: time.Unix(0, 0),
+		} {
+			gf.ZeroDateHandling = mode
+			v := dates{T: time.Now(), NT: nulltypes.NullTime{Val: time.Now()}}
+			v.NT.IsNull = true
+			failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v)))
+			if !v.T.Equal(expected) || !v.NT.Val.Equal(expected) || v.NT.IsNull {
+				t.Fatal(fmt.Sprintf("Zero date for mode %d and “%s” does not match: %+v", mode, query, v))
+			}
+		}
+
+		gf.ZeroDateHandling = gf.ZeroDateError
+		var v dates
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v); err == nil || err.Error() != "Error on T: Zero date is not allowed\nError on NT: Zero date is not allowed" {
+			t.Fatal(fmt.Sprintf("Expected zero date errors for “%s”, got: %v", query, err))
+		}
+	}
+}
+
+func TestEmptyStringIsNull(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+	defer func(on bool) { gf.EmptyStringIsNull = on }(gf.EmptyStringIsNull)
+
+	type empties struct {
+		S   string
+		NS  nulltypes.NullString
+		NI  nulltypes.NullInt64
+		SNS sql.NullString
+		SN  sql.Null[string]
+	}
+	const query = `SELECT '', '', '', '', ''`
+
+	//Off: empty strings are values (and fail to parse into integers)
+	gf.EmptyStringIsNull = false
+	var v empties
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v); err == nil || !strings.HasPrefix(err.Error(), "Error on NI: ") || strings.Count(err.Error(), "Error on ") != 1 {
+		t.Fatal(fmt.Sprintf("Expected only an integer error, got: %v", err))
+	} else if v.NS.IsNull || !v.SNS.Valid || !v.SN.Valid {
+		t.Fatal(fmt.Sprintf("Empty strings were NULL: %+v", v))
+	}
+
+	gf.EmptyStringIsNull = true
+	v = empties{S: "x"}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v)))
+	if v.S != "" || !v.NS.IsNull || !v.NI.IsNull || v.SNS.Valid || v.SN.Valid {
+		t.Fatal(fmt.Sprintf("Empty strings were not NULL: %+v", v))
+	}
+}
+
+func TestErrorFormatter(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type bad struct {
+		A int
+		B string
+		C uint8
+	}
+	const query = `SELECT 'x', 'ok', '300'`
+	_, errA := strconv.ParseInt("x", 10, 64)
+	_, errC := strconv.ParseUint("300", 10, 8)
+
+	//The default output must not change
+	t.Run("Default", func(t *testing.T) {
+		var v bad
+		err := gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v)
+		if expected := "Error on A: " + errA.Error() + "\n" + "Error on C: " + errC.Error(); err == nil || err.Error() != expected {
+			t.Fatal(fmt.Sprintf("Default errors do not match:\n%v\n!=\n%s", err, expected))
+		}
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		defer func(f func(string, error) string, sep string) { gf.ErrorFormatter, gf.ErrorSeparator = f, sep }(gf.ErrorFormatter, gf.ErrorSeparator)
+		gf.ErrorFormatter = func(fieldName string, err error) string { return fmt.Sprintf("[%s]", fieldName) }
+		gf.ErrorSeparator = "; "
+		var v bad
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(query)), &v); err == nil || err.Error() != "[A]; [C]" {
+			t.Fatal(fmt.Sprintf("Custom errors do not match: %v", err))
+		}
+	})
+}
+
+func TestIntBase(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type bases struct {
+		Plain   int
+		Tagged  int                 `gfs:"base:16"`
+		Clamped uint8               `gfs:"base:16,onoverflow:clamp"`
+		Other   int                 `gfs:"onoverflow:clamp"`
+		N       nulltypes.NullInt32 `gfs:"base:2"`
+	}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(bases{})))
+
+	//The base tag takes precedence over DefaultIntBase, and members with other tag options stay in base 10
+	var v bases
+	rr := sm.CreateReaderWithOptions(gf.ReaderOptions{DefaultIntBase: 8})
+	failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT '17', 'ff', '1ff', '17', '101'`))), &v)))
+	if v.Plain != 15 || v.Tagged != 255 || v.Clamped != 255 || v.Other != 17 || v.N.IsNull || v.N.Val != 5 {
+		t.Fatal(fmt.Sprintf("Bases do not match: %+v", v))
+	}
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '17', 'ff', '1ff', '17', NULL`)), &v)))
+	if v.Plain != 17 || v.Tagged != 255 || !v.N.IsNull {
+		t.Fatal(fmt.Sprintf("Default reader bases do not match: %+v", v))
+	}
+
+	for _, d := range []struct {
+		v        any
+		expected string
+	}{
+		{struct {
+			A int `gfs:"onoverflow:clamp,base:16"`
+		}{}, "gfs tag option “base” must be the first option"},
+		{struct {
+			A int `gfs:"base:1"`
+		}{}, "Invalid base “1”"},
+		{struct {
+			A string `gfs:"base:16"`
+		}{}, "Member must be an integer"},
+	} {
+		if _, err := gf.ModelStruct(d.v); err == nil || !strings.Contains(err.Error(), d.expected) {
+			t.Fatal(fmt.Sprintf("Expected “%s”, got: %v", d.expected, err))
+		}
+	}
+}
+
+func TestTimeReaderOptions(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type times struct {
+		T   time.Time
+		NT  nulltypes.NullTime
+		GN  nulltypes.Null[time.Time]
+		SNT sql.NullTime
+		SN  sql.Null[time.Time]
+	}
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	rr := failOnErrT(t, fErr(gf.ModelStruct(times{}))).CreateReaderWithOptions(gf.ReaderOptions{TimeLayouts: []string{"20060102"}, TimeLocation: loc})
+
+	//Every time member uses the layouts and location
+	var v times
+	expected := time.Date(2024, 1, 5, 0, 0, 0, 0, loc)
+	failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT '20240105', '20240105', '20240105', '20240105', '20240105'`))), &v)))
+	for i, tm := range []time.Time{v.T, v.NT.Val, v.GN.Val, v.SNT.Time, v.SN.V} {
+		if !tm.Equal(expected) || tm.Location() != loc {
+			t.Fatal(fmt.Sprintf("Time #%d does not match: %s!=%s", i, tm.String(), expected.String()))
+		}
+	}
+	if v.NT.IsNull || v.GN.IsNull || !v.SNT.Valid || !v.SN.Valid {
+		t.Fatal(fmt.Sprintf("Times were NULL: %+v", v))
+	}
+
+	failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT '2024-01-05 01:02:03', NULL, NULL, NULL, NULL`))), &v)))
+	if !v.T.Equal(expected.Add(time.Hour+2*time.Minute+3*time.Second)) || !v.NT.IsNull || !v.GN.IsNull || v.SNT.Valid || v.SN.Valid || !v.SNT.Time.IsZero() {
+		t.Fatal(fmt.Sprintf("NULL times do not match: %+v", v))
+	}
+}
+
+func TestMaxLen(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//Strings do not split UTF-8 characters, while byte slices are cut at exactly the limit
+	type limited struct {
+		S  string `gfs:"maxlen:2"`
+		B  []byte `gfs:"maxlen:2"`
+		B2 []byte `gfs:"maxlen:3"`
+		E  []byte `gfs:"maxlen:1:error"`
+	}
+	var v limited
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'héllo', X'41C3A9C3', X'8080808080', X'01'`)), &v)))
+	if v.S != "h" || !bytes.Equal(v.B, []byte{0x41, 0xC3}) || !bytes.Equal(v.B2, []byte{0x80, 0x80, 0x80}) || !bytes.Equal(v.E, []byte{1}) {
+		t.Fatal(fmt.Sprintf("Truncated values do not match: %+v", v))
+	}
+	if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'a', X'01', X'01', X'0102'`)), &v); err == nil || err.Error() != "Error on E: Length 2 is greater than the maximum of 1" {
+		t.Fatal(fmt.Sprintf("Expected a length error, got: %v", err))
+	}
+}
+
+func TestZeroNullMembersDateTime2Col(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	//The time column comes first, so zeroing the member for the NULL date would lose its time of day
+	type split struct {
+		T time.Time `gfs:"datetime2col"`
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(split{}))).CreateReaderNamedWithOptions(gf.ReaderOptions{ZeroNullMembers: true})
+	var v split
+	failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query("SELECT '12:30:00' AS `T.time`, NULL AS `T.date`"))), &v)))
+	if expected := time.Unix(0, 0).Add(12*time.Hour + 30*time.Minute); !v.T.Equal(expected) {
+		t.Fatal(fmt.Sprintf("Time does not match: %s!=%s", v.T.String(), expected.String()))
+	}
+}
+
+func TestInvalidTypeErrors(t *testing.T) {
+	type badMember struct {
+		A int
+		C chan int
+	}
+
+	//The type is named once for a single structure, and once per parameter for multiple variables
+	if _, err := gf.ModelStruct(badMember{}); err == nil || err.Error() != "test.badMember: Invalid types found for members:\nC: chan int" {
+		t.Fatal(fmt.Sprintf("Single structure error does not match: %v", err))
+	}
+	var i int
+	if _, err := gf.ModelStruct(&i, badMember{}); err == nil || err.Error() != "Parameter #1 of type “test.badMember” has errors:\nInvalid types found for members:\nC: chan int" {
+		t.Fatal(fmt.Sprintf("Multiple variable error does not match: %v", err))
+	}
+}
+
+func TestLazyTime(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type lazy struct {
+		A, B, N gf.LazyTime
+	}
+	var v lazy
+	failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT '2024-01-05 01:02:03', '20240105', NULL`)), &v)))
+	if v.A.Raw != "2024-01-05 01:02:03" || v.B.Raw != "20240105" || !v.N.IsNull() {
+		t.Fatal(fmt.Sprintf("Raw times do not match: %+v", v))
+	}
+
+	//Time parses like a reader without options, and TimeWith like a reader with the time options
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	if tm := failOnErrT(t, fErr(v.A.Time())); !tm.Equal(time.Date(2024, 1, 5, 1, 2, 3, 0, time.UTC)) {
+		t.Fatal(fmt.Sprintf("Time does not match: %s", tm.String()))
+	} else if tm := failOnErrT(t, fErr(v.A.TimeWith(nil, loc))); !tm.Equal(time.Date(2024, 1, 5, 1, 2, 3, 0, loc)) {
+		t.Fatal(fmt.Sprintf("Time in location does not match: %s", tm.String()))
+	} else if tm := failOnErrT(t, fErr(v.B.TimeWith([]string{"20060102"}, loc))); !tm.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, loc)) {
+		t.Fatal(fmt.Sprintf("Time with layouts does not match: %s", tm.String()))
+	}
+}
+
+func TestLazyRow(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type lazyScan struct {
+		Created    time.Time
+		CreatedStr string `gfs:"copyof:Created"`
+		Kind       string
+		Val        any `gfs:"resolve:lazyRowTest:Kind"`
+	}
+	gf.RegisterTypeResolver("lazyRowTest", func(string) (any, error) { return new(int), nil })
+	rr := failOnErrT(t, fErr(gf.ModelStruct(lazyScan{}))).CreateReader()
+	rows := failOnErrT(t, fErr(tx.Query(`SELECT '2001-02-03 04:05:06', 'int', '5'`)))
+	defer rows.Close()
+	rows.Next()
+	lr := failOnErrT(t, fErr(rr.ScanLazy(rows)))
+
+	t.Run("Getters", func(t *testing.T) {
+		if str := failOnErrT(t, fErr(lr.String("Created"))); str != "2001-02-03 04:05:06" {
+			t.Fatal("Lazy string did not match: " + str)
+		}
+		if n := failOnErrT(t, fErr(lr.Int("Val"))); n != 5 {
+			t.Fatal(fmt.Sprintf("Lazy int did not match: %d", n))
+		}
+		var kind string
+		if failOnErrT(t, fErr(0, lr.Scan("Kind", &kind))); kind != "int" {
+			t.Fatal("Lazy scan did not match: " + kind)
+		}
+	})
+
+	//Members whose conversion accesses other members of the structure cannot be scanned into standalone variables
+	t.Run("Needs parent structure", func(t *testing.T) {
+		var created time.Time
+		if err := lr.Scan("Created", &created); !errors.Is(err, gf.ErrNeedsParentStruct) {
+			t.Fatal(fmt.Sprintf("Expected ErrNeedsParentStruct for a copied member: %v", err))
+		}
+		var val any
+		if err := lr.Scan("Val", &val); !errors.Is(err, gf.ErrNeedsParentStruct) {
+			t.Fatal(fmt.Sprintf("Expected ErrNeedsParentStruct for a resolved member: %v", err))
+		}
+	})
+}
+
+func TestColumnCountErrors(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type twoCols struct {
+		A, B int
+	}
+
+	for named, scan := range []func(gf.SRErrStruct, ...any) error{gf.ScanRowWErr, gf.ScanRowNamedWErr} {
+		if err := scan(gf.SRErr(tx.Query(`SELECT 1 AS A`)), new(twoCols)); !errors.Is(err, gf.ErrTooFewColumns) {
+			t.Fatal(fmt.Sprintf("Expected ErrTooFewColumns (scan function #%d): %v", named, err))
+		}
+		if err := scan(gf.SRErr(tx.Query(`SELECT 1 AS A, 2 AS B, 3 AS C`)), new(twoCols)); !errors.Is(err, gf.ErrTooManyColumns) {
+			t.Fatal(fmt.Sprintf("Expected ErrTooManyColumns (scan function #%d): %v", named, err))
+		}
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type oneCol struct {
+		A int
+	}
+	rr := failOnErrT(t, fErr(gf.ModelStruct(oneCol{}))).CreateReader()
+
+	var v oneCol
+	failOnErrT(t, fErr(0, rr.ScanOne(failOnErrT(t, fErr(tx.Query(`SELECT 1`))), &v)))
+	if v.A != 1 {
+		t.Fatal(fmt.Sprintf("Single row did not match: %d", v.A))
+	}
+	if err := rr.ScanOne(failOnErrT(t, fErr(tx.Query(`SELECT 2 UNION ALL SELECT 3`))), &v); !errors.Is(err, gf.ErrMultipleRows) || v.A != 2 {
+		t.Fatal(fmt.Sprintf("Expected ErrMultipleRows with the first row scanned: %v %d", err, v.A))
+	}
+	if err := rr.ScanOne(failOnErrT(t, fErr(tx.Query(`SELECT 1 FROM DUAL WHERE 0`))), &v); err != sql.ErrNoRows {
+		t.Fatal(fmt.Sprintf("Expected sql.ErrNoRows: %v", err))
+	}
+}
+
+func TestCopyOf(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type copies struct {
+		ID         int
+		CreatedStr string `gfs:"copyof:Created"`
+		Created    time.Time
+		Name       string
+	}
+
+	t.Run("Scan", func(t *testing.T) {
+		var v copies
+		failOnErrT(t, fErr(0, gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 1, '2001-02-03 04:05:06', 'n'`)), &v)))
+		if v.ID != 1 || v.CreatedStr != "2001-02-03 04:05:06" || !v.Created.Equal(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)) || v.Name != "n" {
+			t.Fatal(fmt.Sprintf("Copied member did not match: %+v", v))
+		}
+	})
+
+	t.Run("Scan Named", func(t *testing.T) {
+		var v copies
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT 'n' AS Name, '2001-02-03 04:05:06' AS Created, 1 AS ID`)), &v)))
+		if v.ID != 1 || v.CreatedStr != "2001-02-03 04:05:06" || !v.Created.Equal(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)) || v.Name != "n" {
+			t.Fatal(fmt.Sprintf("Named copied member did not match: %+v", v))
+		}
+	})
+
+	//Replacing the conversion function of the copied member still fills the copy
+	t.Run("Replaced converter", func(t *testing.T) {
+		rr := failOnErrT(t, fErr(failOnErrT(t, fErr(gf.ModelStruct(copies{}))).CreateReaderWithConverters(map[string]func([]byte, unsafe.Pointer) error{
+			"Created": func(in []byte, p unsafe.Pointer) error { *(*time.Time)(p) = time.Unix(int64(len(in)), 0); return nil },
+		})))
+		var v copies
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 1, '2001-02-03 04:05:06', 'n'`))), &v)))
+		if v.CreatedStr != "2001-02-03 04:05:06" || !v.Created.Equal(time.Unix(19, 0)) {
+			t.Fatal(fmt.Sprintf("Copied member with a replaced converter did not match: %+v", v))
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		type badCopy struct {
+			A *string `gfs:"copyof:B"`
+			B time.Time
+			C string `gfs:"copyof:Missing"`
+		}
+		if _, err := gf.ModelStruct(badCopy{}); err == nil {
+			t.Fatal("Expected an error for invalid copies")
+		}
+	})
+}
+
+func TestNullTypesJSON(t *testing.T) {
+	type nulls struct {
+		U8  nulltypes.NullUint8
+		U16 nulltypes.NullUint16
+		U32 nulltypes.NullUint32
+		U64 nulltypes.NullUint64
+		I8  nulltypes.NullInt8
+		I16 nulltypes.NullInt16
+		I32 nulltypes.NullInt32
+		I64 nulltypes.NullInt64
+		F32 nulltypes.NullFloat32
+		F64 nulltypes.NullFloat64
+		B   nulltypes.NullBool
+		S   nulltypes.NullString
+		BA  nulltypes.NullByteArray
+		RB  nulltypes.NullRawBytes
+		T   nulltypes.NullTime
+		GB  nulltypes.Null[[]byte] //Byte slices are strings like NullByteArray instead of base64
+		GR  nulltypes.Null[sql.RawBytes]
+		GI  nulltypes.Null[int]
+	}
+	resArr := []string{
+		`{"U8":255,"U16":65535,"U32":4294967295,"U64":18446744073709551615,"I8":-128,"I16":-32768,"I32":-2147483648,"I64":-9223372036854775808,"F32":1.5,"F64":-2.25,"B":true,"S":"str","BA":"ba","RB":"rb","T":"2001-02-03T04:05:06.7Z","GB":"ba","GR":"rb","GI":5}`,
+		`{"U8":null,"U16":null,"U32":null,"U64":null,"I8":null,"I16":null,"I32":null,"I64":null,"F32":null,"F64":null,"B":null,"S":null,"BA":null,"RB":null,"T":null,"GB":null,"GR":null,"GI":null}`,
+	}
+
+	for i, res := range resArr {
+		var v nulls
+		failOnErrT(t, fErr(0, json.Unmarshal([]byte(res), &v)))
+		if str := failOnErrT(t, fErr(json.Marshal(v))); string(str) != res {
+			t.Fatal(fmt.Sprintf("Null types json round trip #%d did not match: %s", i+1, string(str)))
+		}
+	}
+
+	//The same bytes give the same JSON from either type
+	if ba, gb := failOnErrT(t, fErr(json.Marshal(nulltypes.NullByteArray{Val: []byte("x y")}))), failOnErrT(t, fErr(json.Marshal(nulltypes.Null[[]byte]{Val: []byte("x y")}))); string(ba) != string(gb) {
+		t.Fatal(fmt.Sprintf("Byte slice JSON does not match: %s!=%s", ba, gb))
+	}
+
+	//A null must clear a prior value
+	v := nulltypes.NullString{Val: "prior"}
+	failOnErrT(t, fErr(0, json.Unmarshal([]byte(`null`), &v)))
+	if !v.IsNull || v.Val != "" {
+		t.Fatal(fmt.Sprintf("Null did not clear the prior value: %s", v.Val))
+	}
+	gb := nulltypes.Null[[]byte]{Val: []byte("prior")}
+	failOnErrT(t, fErr(0, json.Unmarshal([]byte(`null`), &gb)))
+	if !gb.IsNull || gb.Val != nil {
+		t.Fatal(fmt.Sprintf("Null did not clear the prior byte slice: %s", gb.Val))
+	}
+
+	if err := json.Unmarshal([]byte(`256`), new(nulltypes.NullUint8)); err == nil {
+		t.Fatal("Expected an error for an out of range value")
+	}
+}
+
+func TestAnonymousStruct(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	v := &struct {
+		Name  string `db:"full_name"`
+		Age   int    `db:"age"`
+		Inner struct {
+			Zip string `db:"zip"`
+		} `db:"addr"`
+	}{}
+	sm := failOnErrT(t, fErr(gf.ModelStruct(v)))
+
+	//The db tag names are opt-in
+	t.Run("DBTagNames", func(t *testing.T) {
+		rr := sm.CreateReaderNamedWithOptions(gf.ReaderOptions{DBTagNames: true})
+		failOnErrT(t, fErr(0, rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 42 AS age, '12345' AS zip, 'Ada Lovelace' AS full_name`))), v)))
+		if v.Name != "Ada Lovelace" || v.Age != 42 || v.Inner.Zip != "12345" {
+			t.Fatal(fmt.Sprintf("Anonymous structure did not match: %+v", *v))
+		}
+		if err := rr.ScanRow(failOnErrT(t, fErr(tx.Query(`SELECT 'x' AS age, '1' AS zip, 'a' AS full_name`))), v); err == nil || !strings.HasPrefix(err.Error(), "Error on age: ") {
+			t.Fatal(fmt.Sprintf("Expected an error named by the db tag, got: %v", err))
+		}
+		if err := gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT 42 AS age, '12345' AS `+"`addr.zip`"+`, 'Ada Lovelace' AS full_name`)), v); err == nil {
+			t.Fatal("Expected the db tag names to not be used by default")
+		}
+	})
+
+	//Without the option, members with db tags keep matching by their Go names
+	t.Run("Go names", func(t *testing.T) {
+		*v = struct {
+			Name  string `db:"full_name"`
+			Age   int    `db:"age"`
+			Inner struct {
+				Zip string `db:"zip"`
+			} `db:"addr"`
+		}{}
+		failOnErrT(t, fErr(0, gf.ScanRowNamedWErr(gf.SRErr(tx.Query(`SELECT 42 AS Age, '12345' AS `+"`Inner.Zip`"+`, 'Ada Lovelace' AS Name`)), v)))
+		if v.Name != "Ada Lovelace" || v.Age != 42 || v.Inner.Zip != "12345" {
+			t.Fatal(fmt.Sprintf("Anonymous structure did not match: %+v", *v))
+		}
+		if err := gf.ScanRowWErr(gf.SRErr(tx.Query(`SELECT 'a', 'x', '1'`)), v); err == nil || !strings.HasPrefix(err.Error(), "Error on Age: ") {
+			t.Fatal(fmt.Sprintf("Expected an error named by the Go name, got: %v", err))
+		}
+	})
+}
+
+func TestScanAllRowsNamed(t *testing.T) {
+	//Connect to the database and create a transaction
+	tx := failOnErrT(t, fErr(setupSQLConnect()))
+	defer rollbackTransactionAndRows(tx, nil, 0)
+
+	type t1 struct {
+		A int
+		B string
+	}
+	rrn := failOnErrT(t, fErr(gf.ModelStruct(t1{}))).CreateReaderNamed()
+
+	t.Run("Values", func(t *testing.T) {
+		var out []t1
+		failOnErrT(t, fErr(0, rrn.ScanAllRows(failOnErrT(t, fErr(tx.Query(`SELECT 'x' AS B, 1 AS A UNION ALL SELECT 'y', 2`))), &out)))
+		if len(out) != 2 || out[0] != (t1{1, "x"}) || out[1] != (t1{2, "y"}) {
+			t.Fatal(fmt.Sprintf("Rows did not match: %+v", out))
+		}
+	})
+
+	t.Run("Pointers", func(t *testing.T) {
+		var out []*t1
+		failOnErrT(t, fErr(0, rrn.ScanAllRows(failOnErrT(t, fErr(tx.Query(`SELECT 'z' AS B, 3 AS A`))), &out)))
+		if len(out) != 1 || *out[0] != (t1{3, "z"}) {
+			t.Fatal(fmt.Sprintf("Rows did not match: %+v", out))
+		}
+	})
+
+	//Only the appended elements of the backing array are written to (and zeroed first)
+	t.Run("Shared capacity", func(t *testing.T) {
+		type t2 struct {
+			A     int
+			B     string
+			Extra int `gfs:"-"`
+		}
+		rr := failOnErrT(t, fErr(gf.ModelStruct(t2{}))).CreateReader()
+		shared := make([]t2, 4)
+		shared[1].Extra, shared[2].Extra = 7, 9
+		out := shared[:1]
+		failOnErrT(t, fErr(0, rr.ScanAllRows(failOnErrT(t, fErr(tx.Query(`SELECT 1, 'x' FROM DUAL WHERE 0`))), &out)))
+		if len(out) != 1 || shared[1].Extra != 7 || shared[2].Extra != 9 {
+			t.Fatal(fmt.Sprintf("Scanning no rows modified the backing array: %+v", shared))
+		}
+		failOnErrT(t, fErr(0, rr.ScanAllRows(failOnErrT(t, fErr(tx.Query(`SELECT 1, 'x'`))), &out)))
+		if len(out) != 2 || out[1] != (t2{1, "x", 0}) || shared[2].Extra != 9 {
+			t.Fatal(fmt.Sprintf("Appended row did not match or modified the rest of the backing array: %+v", shared))
+		}
+	})
+}
@@ -16,6 +16,12 @@ func interface2Pointer(v any) unsafe.Pointer {
 	return (*(*struct{ _, Data unsafe.Pointer })(unsafe.Pointer(&v))).Data
 }
 
+// pointer2Interface (Unsafe!) returns a copy of the interface (which must hold a pointer) with its pointer replaced by p
+func pointer2Interface[I any](proto I, p unsafe.Pointer) I {
+	(*struct{ _, Data unsafe.Pointer })(unsafe.Pointer(&proto)).Data = p
+	return proto
+}
+
 // cond is basically the conditional operator. Unfortunately, both paths are still evaluated so only use this when there is no extra processing for both paths.
 func cond[T any](isTrue bool, ifTrue, ifFalse T) T {
 	if isTrue {
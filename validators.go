@@ -0,0 +1,47 @@
+//Per type validation of converted members
+
+package gofastersql
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Validator checks a converted member of its registered type (p points to the member). See RegisterValidator
+type Validator func(p unsafe.Pointer) error
+
+var validators = make(map[reflect.Type]Validator)
+var validatorsLock sync.RWMutex
+
+/*
+RegisterValidator registers a Validator that is called after each member (or scalar variable) of type t is converted, and whose error is returned like a conversion error (ex: type Price float64 must not be negative).
+
+Validators are added to models when they are created, so they should be registered before the types are first modeled. Registering a type again replaces its validator, though models that were already created keep the validator they found. Passing nil removes the validator.
+*/
+func RegisterValidator(t reflect.Type, fn Validator) {
+	validatorsLock.Lock()
+	if fn == nil {
+		delete(validators, t)
+	} else {
+		validators[t] = fn
+	}
+	validatorsLock.Unlock()
+}
+
+// addValidator wraps the conversion function of a member of type t with its registered Validator, if there is one. Validated members lose the flags that allow ReaderOptions to replace their conversion function.
+func addValidator(t reflect.Type, fn converterFunc, sff structFieldFlags) (converterFunc, structFieldFlags) {
+	validatorsLock.RLock()
+	validator := validators[t]
+	validatorsLock.RUnlock()
+	if validator == nil || fn == nil {
+		return fn, sff
+	}
+
+	return func(in []byte, p upt) error {
+		if err := fn(in, p); err != nil {
+			return err
+		}
+		return validator(unsafe.Pointer(p))
+	}, sff &^ sffDefaultConverters
+}